@@ -0,0 +1,72 @@
+package r85
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"net/netip"
+)
+
+// addrLen is the length of an address in the representation of RFC 1924.
+// 85^20 > 2^128 > 85^19.
+const addrLen = 20
+
+// FormatAddr returns the RFC 1924 representation of a: the 128-bit
+// address as a single base-85 number of 20 digits, using the RFC 1924
+// alphabet.  An IPv4 address is formatted as its IPv4-mapped IPv6
+// address, and any zone is dropped.
+func FormatAddr(a netip.Addr) string {
+	return RFC1924Encoding.FormatAddr(a)
+}
+
+// ParseAddr parses an address in the representation of RFC 1924.
+func ParseAddr(s string) (netip.Addr, error) {
+	return RFC1924Encoding.ParseAddr(s)
+}
+
+// FormatAddr returns a as a single 20-digit base-85 number using enc's
+// alphabet, as [FormatAddr] does for RFC 1924.  Unlike Encode, which
+// encodes each 32-bit block separately, the whole address is converted
+// with 128-bit division, so enc's partial-block rule, shortcuts and
+// framing are not used.
+func (enc *Encoding) FormatAddr(a netip.Addr) string {
+	b := a.As16()
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	var buf [addrLen]byte
+	for i := addrLen - 1; i >= 0; i-- {
+		var r uint64
+		hi, r = bits.Div64(0, hi, 85)
+		lo, r = bits.Div64(r, lo, 85)
+		buf[i] = enc.encode[r]
+	}
+	return string(buf[:])
+}
+
+// ParseAddr parses an address formatted by enc.FormatAddr.  The result
+// is always an IPv6 address.  s must consist of exactly 20 digits of
+// enc's alphabet.
+func (enc *Encoding) ParseAddr(s string) (netip.Addr, error) {
+	if len(s) != addrLen {
+		return netip.Addr{}, CorruptInputError{Reason: "address is not 20 characters long"}
+	}
+	var hi, lo uint64
+	for i := range addrLen {
+		v := enc.decodeMap[s[i]]
+		if v >= 85 {
+			return netip.Addr{}, CorruptInputError{Reason: "invalid character in address"}
+		}
+		// (hi, lo) = (hi, lo)*85 + v
+		c, h := bits.Mul64(hi, 85)
+		carry, l := bits.Mul64(lo, 85)
+		l, c2 := bits.Add64(l, uint64(v), 0)
+		h, c3 := bits.Add64(h, carry, c2)
+		if c != 0 || c3 != 0 {
+			return netip.Addr{}, CorruptInputError{Reason: "address value overflow"}
+		}
+		hi, lo = h, l
+	}
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], hi)
+	binary.BigEndian.PutUint64(b[8:], lo)
+	return netip.AddrFrom16(b), nil
+}
@@ -0,0 +1,55 @@
+package r85
+
+import (
+	"net/netip"
+	"testing"
+)
+
+// TestFormatAddrRFC1924 tests the example from RFC 1924.
+func TestFormatAddrRFC1924(t *testing.T) {
+	a := netip.MustParseAddr("1080:0:0:0:8:800:200C:417A")
+	const want = "4)+k&C#VzJ4br>0wv%Yp"
+	if got := FormatAddr(a); got != want {
+		t.Errorf("FormatAddr(%v) = %q, want %q", a, got, want)
+	}
+	got, err := ParseAddr(want)
+	if err != nil || got != a {
+		t.Errorf("ParseAddr(%q) = %v, %v, want %v", want, got, err, a)
+	}
+}
+
+// TestAddrRoundtrip tests both alphabets at the extremes of the range.
+func TestAddrRoundtrip(t *testing.T) {
+	addrs := []string{"::", "::1", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "2001:db8::8a2e:370:7334", "::ffff:192.0.2.1"}
+	for _, enc := range []*Encoding{RFC1924Encoding, StdEncoding} {
+		for _, s := range addrs {
+			a := netip.MustParseAddr(s)
+			text := enc.FormatAddr(a)
+			if len(text) != 20 {
+				t.Errorf("FormatAddr(%v) = %q: length %d", a, text, len(text))
+			}
+			got, err := enc.ParseAddr(text)
+			if err != nil || got != a {
+				t.Errorf("ParseAddr(%q) = %v, %v, want %v", text, got, err, a)
+			}
+		}
+	}
+	if got := StdEncoding.FormatAddr(netip.IPv6Unspecified()); got != "((((((((((((((((((((" {
+		t.Errorf("FormatAddr(::) = %q", got)
+	}
+}
+
+// TestParseAddrErrors tests rejection of malformed addresses.
+func TestParseAddrErrors(t *testing.T) {
+	for _, s := range []string{"", "4)+k&C#VzJ4br>0wv%Y", "4)+k&C#VzJ4br>0wv%Y\"", "~~~~~~~~~~~~~~~~~~~~"} {
+		if _, err := ParseAddr(s); err == nil {
+			t.Errorf("ParseAddr(%q): expected error, got nil", s)
+		}
+	}
+	// An IPv4 address is formatted as IPv4-mapped IPv6.
+	a := netip.MustParseAddr("192.0.2.1")
+	got, err := ParseAddr(FormatAddr(a))
+	if err != nil || got.Unmap() != a {
+		t.Errorf("IPv4 roundtrip = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,329 @@
+package r85
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// armorLineLen is the number of r85 characters per armored body line.
+// It is a multiple of 5 so that lines break on block boundaries.
+const armorLineLen = 75
+
+const (
+	armorBegin = "-----BEGIN R85"
+	armorEnd   = "-----END R85"
+	armorDash  = "-----"
+)
+
+// Errors returned by [NewArmorDecoder] and [ArmorDecoder.Read].
+var (
+	ErrArmorNotFound  = errors.New("r85: no armor BEGIN line found")
+	ErrArmorTruncated = errors.New("r85: armor END line missing")
+	ErrArmorMalformed = errors.New("r85: malformed armor END line")
+	ErrArmorLabel     = errors.New("r85: armor END label does not match BEGIN label")
+	ErrArmorLength    = errors.New("r85: armor length mismatch")
+	ErrArmorChecksum  = errors.New("r85: armor CRC-32 mismatch")
+)
+
+// ErrArmorHeader is returned by the writer from [NewArmorEncoder] if the
+// label or a header cannot be written without breaking the armor.
+var ErrArmorHeader = errors.New("r85: invalid armor label or header")
+
+// NewArmorEncoder returns a writer that wraps the r85 encoding of the data
+// written to it in an armor block:
+//
+//	-----BEGIN R85 <label>-----
+//	Key: value
+//
+//	<body lines of 75 characters>
+//	-----END R85 <label> <length> <crc32>-----
+//
+// Headers are written in sorted key order, followed by a blank line; both
+// are omitted if headers is empty.  The END line records the decoded byte
+// count in decimal and the IEEE CRC-32 of the decoded data in hex, so that
+// [NewArmorDecoder] can detect truncation and corruption.
+// Nothing is written until the first Write or Close, and the END line is
+// only written by Close.
+//
+// The label and the header values must not contain line breaks, and the
+// header keys must also be nonempty and must not contain ": ", which
+// separates them from their values; otherwise Write and Close write
+// nothing and return [ErrArmorHeader].
+func NewArmorEncoder(w io.Writer, label string, headers map[string]string) io.WriteCloser {
+	a := &armorEncoder{w: w, label: label, headers: headers}
+	a.lines = lineWriter{w: w, width: armorLineLen, sep: []byte{'\n'}}
+	a.enc = NewEncoder(&a.lines)
+	return a
+}
+
+type armorEncoder struct {
+	w       io.Writer
+	label   string
+	headers map[string]string
+	started bool
+	lines   lineWriter
+	enc     io.WriteCloser
+	n       int64
+	crc     uint32
+	err     error
+}
+
+func (a *armorEncoder) start() error {
+	if a.started {
+		return a.err
+	}
+	a.started = true
+	if !validArmorHeaders(a.label, a.headers) {
+		a.err = ErrArmorHeader
+		return a.err
+	}
+	var b strings.Builder
+	b.WriteString(armorBegin)
+	if a.label != "" {
+		b.WriteByte(' ')
+		b.WriteString(a.label)
+	}
+	b.WriteString(armorDash + "\n")
+	for _, k := range slices.Sorted(maps.Keys(a.headers)) {
+		b.WriteString(k + ": " + a.headers[k] + "\n")
+	}
+	if len(a.headers) > 0 {
+		b.WriteByte('\n')
+	}
+	_, a.err = io.WriteString(a.w, b.String())
+	return a.err
+}
+
+// validArmorHeaders reports whether label and headers can be written as
+// NewArmorEncoder describes.
+func validArmorHeaders(label string, headers map[string]string) bool {
+	if strings.ContainsAny(label, "\r\n") {
+		return false
+	}
+	for k, v := range headers {
+		if k == "" || strings.Contains(k, ": ") || strings.ContainsAny(k+v, "\r\n") {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *armorEncoder) Write(p []byte) (int, error) {
+	if err := a.start(); err != nil {
+		return 0, err
+	}
+	n, err := a.enc.Write(p)
+	a.crc = crc32.Update(a.crc, crc32.IEEETable, p[:n])
+	a.n += int64(n)
+	if err != nil {
+		a.err = err
+	}
+	return n, err
+}
+
+func (a *armorEncoder) Close() error {
+	if err := a.start(); err != nil {
+		return err
+	}
+	if a.err = a.enc.Close(); a.err != nil {
+		return a.err
+	}
+	var b strings.Builder
+	if a.lines.col > 0 {
+		b.WriteByte('\n')
+	}
+	b.WriteString(armorEnd)
+	if a.label != "" {
+		b.WriteByte(' ')
+		b.WriteString(a.label)
+	}
+	fmt.Fprintf(&b, " %d %08x%s\n", a.n, a.crc, armorDash)
+	_, a.err = io.WriteString(a.w, b.String())
+	return a.err
+}
+
+// lineWriter copies bytes to w, inserting sep before every byte that
+// would start a new line of width bytes.
+type lineWriter struct {
+	w     io.Writer
+	width int
+	col   int
+	sep   []byte
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if l.col == l.width {
+			if _, err := l.w.Write(l.sep); err != nil {
+				return n, err
+			}
+			l.col = 0
+		}
+		k := min(len(p), l.width-l.col)
+		m, err := l.w.Write(p[:k])
+		n += m
+		l.col += m
+		if err != nil {
+			return n, err
+		}
+		p = p[k:]
+	}
+	return n, nil
+}
+
+// ArmorDecoder reads the data from an armor block written by
+// [NewArmorEncoder].
+type ArmorDecoder struct {
+	// Label is the label from the BEGIN line.
+	Label string
+	// Headers holds the "Key: value" lines that follow the BEGIN line.
+	Headers map[string]string
+
+	body armorBody
+	dec  io.Reader
+	n    int64
+	crc  uint32
+	err  error
+}
+
+// NewArmorDecoder scans r for the first BEGIN line of an armor block,
+// skipping any text before it, and parses the headers that follow.
+// Reads from the returned decoder yield the decoded body.  Once the body
+// is exhausted, the length and CRC-32 from the END line are checked, and
+// Read returns io.EOF only if both match.  Text after the END line is
+// ignored.
+func NewArmorDecoder(r io.Reader) (*ArmorDecoder, error) {
+	br := bufio.NewReader(r)
+	a := &ArmorDecoder{Headers: make(map[string]string)}
+	for {
+		line, err := br.ReadString('\n')
+		s := strings.TrimSpace(line)
+		if strings.HasPrefix(s, armorBegin) && strings.HasSuffix(s, armorDash) && len(s) >= len(armorBegin)+len(armorDash) {
+			a.Label = strings.Join(strings.Fields(s[len(armorBegin):len(s)-len(armorDash)]), " ")
+			break
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = ErrArmorNotFound
+			}
+			return nil, err
+		}
+	}
+
+	// Body lines never contain spaces, so any line with ": " is a header.
+	// A blank line ends the headers.
+	var first []byte
+	for {
+		line, err := br.ReadString('\n')
+		s := strings.TrimRight(line, "\r\n")
+		if k, v, ok := strings.Cut(s, ": "); ok && !strings.HasPrefix(s, armorEnd) {
+			a.Headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		} else if strings.TrimSpace(s) != "" || err != nil {
+			first = []byte(line)
+			break
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	a.body = armorBody{br: br, label: a.Label, next: first}
+	a.dec = NewDecoder(&a.body)
+	return a, nil
+}
+
+// Read decodes armored data into p.
+func (a *ArmorDecoder) Read(p []byte) (int, error) {
+	if a.err != nil {
+		return 0, a.err
+	}
+	n, err := a.dec.Read(p)
+	a.crc = crc32.Update(a.crc, crc32.IEEETable, p[:n])
+	a.n += int64(n)
+	if err == io.EOF {
+		switch {
+		case a.n != a.body.n:
+			err = ErrArmorLength
+		case a.crc != a.body.crc:
+			err = ErrArmorChecksum
+		}
+	}
+	if err != nil {
+		a.err = err
+	}
+	return n, err
+}
+
+// armorBody yields the body lines of an armor block, stopping at the END
+// line and recording the length and checksum it carries.
+type armorBody struct {
+	br    *bufio.Reader
+	label string
+	next  []byte // a line read ahead by NewArmorDecoder
+	line  []byte
+	done  bool
+	n     int64
+	crc   uint32
+}
+
+func (b *armorBody) Read(p []byte) (int, error) {
+	for len(b.line) == 0 {
+		if b.done {
+			return 0, io.EOF
+		}
+		line, err := b.next, error(nil)
+		if line == nil {
+			line, err = b.br.ReadBytes('\n')
+		}
+		b.next = nil
+		if bytes.HasPrefix(bytes.TrimSpace(line), []byte(armorEnd)) {
+			b.done = true
+			if perr := b.parseEnd(string(bytes.TrimSpace(line))); perr != nil {
+				return 0, perr
+			}
+			continue
+		}
+		if len(line) == 0 && err != nil {
+			if err == io.EOF {
+				err = ErrArmorTruncated
+			}
+			return 0, err
+		}
+		b.line = line
+	}
+	n := copy(p, b.line)
+	b.line = b.line[n:]
+	return n, nil
+}
+
+func (b *armorBody) parseEnd(s string) error {
+	if !strings.HasSuffix(s, armorDash) || len(s) < len(armorEnd)+len(armorDash) {
+		return ErrArmorMalformed
+	}
+	f := strings.Fields(s[len(armorEnd) : len(s)-len(armorDash)])
+	if len(f) < 2 {
+		return ErrArmorMalformed
+	}
+	n, err := strconv.ParseInt(f[len(f)-2], 10, 64)
+	if err != nil || n < 0 {
+		return ErrArmorMalformed
+	}
+	crc, err := strconv.ParseUint(f[len(f)-1], 16, 32)
+	if err != nil {
+		return ErrArmorMalformed
+	}
+	if strings.Join(f[:len(f)-2], " ") != b.label {
+		return ErrArmorLabel
+	}
+	b.n, b.crc = n, uint32(crc)
+	return nil
+}
@@ -0,0 +1,140 @@
+package r85
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func armor(t *testing.T, label string, headers map[string]string, src []byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewArmorEncoder(&buf, label, headers)
+	if _, err := w.Write(src); err != nil {
+		t.Fatalf("armor Write: err = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("armor Close: err = %v", err)
+	}
+	return buf.String()
+}
+
+// TestArmorRoundtrip tests an armor block embedded in surrounding text.
+func TestArmorRoundtrip(t *testing.T) {
+	src := makeSrc(1000)
+	headers := map[string]string{"Name": "blob.bin", "Comment": "test data"}
+	text := "Some preamble.\n\n" + armor(t, "TEST BLOB", headers, src) + "Trailing text.\n"
+
+	lines := strings.Split(text, "\n")
+	if lines[2] != "-----BEGIN R85 TEST BLOB-----" {
+		t.Errorf("BEGIN line = %q", lines[2])
+	}
+	if lines[3] != "Comment: test data" || lines[4] != "Name: blob.bin" || lines[5] != "" {
+		t.Errorf("header lines = %q", lines[3:6])
+	}
+	if len(lines[6]) != armorLineLen {
+		t.Errorf("body line length = %d, want %d", len(lines[6]), armorLineLen)
+	}
+
+	d, err := NewArmorDecoder(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("NewArmorDecoder: err = %v", err)
+	}
+	if d.Label != "TEST BLOB" {
+		t.Errorf("Label = %q", d.Label)
+	}
+	if d.Headers["Name"] != "blob.bin" || d.Headers["Comment"] != "test data" {
+		t.Errorf("Headers = %v", d.Headers)
+	}
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll: err = %v", err)
+	}
+	if !bytes.Equal(got, src) {
+		t.Errorf("armor roundtrip mismatch")
+	}
+}
+
+// TestArmorEmpty tests an armor block with no headers and no data.
+func TestArmorEmpty(t *testing.T) {
+	text := armor(t, "EMPTY", nil, nil)
+	want := "-----BEGIN R85 EMPTY-----\n-----END R85 EMPTY 0 00000000-----\n"
+	if text != want {
+		t.Errorf("empty armor = %q, want %q", text, want)
+	}
+	d, err := NewArmorDecoder(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("NewArmorDecoder: err = %v", err)
+	}
+	got, err := io.ReadAll(d)
+	if err != nil || len(got) != 0 {
+		t.Errorf("ReadAll = %q, %v", got, err)
+	}
+}
+
+// TestArmorBadHeaders verifies that labels and headers that would break
+// the armor are rejected before anything is written.
+func TestArmorBadHeaders(t *testing.T) {
+	tests := []struct {
+		label   string
+		headers map[string]string
+	}{
+		{"TWO\nLINES", nil},
+		{"MSG", map[string]string{"": "empty key"}},
+		{"MSG", map[string]string{"Key: Value": "x"}},
+		{"MSG", map[string]string{"Key\n\nBody": "x"}},
+		{"MSG", map[string]string{"Key": "two\nlines"}},
+		{"MSG", map[string]string{"Key": "carriage\rreturn"}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := NewArmorEncoder(&buf, tt.label, tt.headers)
+		if _, err := w.Write([]byte("data")); err != ErrArmorHeader {
+			t.Errorf("%q, %q: Write err = %v, want ErrArmorHeader", tt.label, tt.headers, err)
+		}
+		if err := w.Close(); err != ErrArmorHeader {
+			t.Errorf("%q, %q: Close err = %v, want ErrArmorHeader", tt.label, tt.headers, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%q, %q: wrote %q", tt.label, tt.headers, buf.String())
+		}
+	}
+	// A value may contain ": ".
+	text := armor(t, "MSG", map[string]string{"Note": "a: b"}, nil)
+	if d, err := NewArmorDecoder(strings.NewReader(text)); err != nil || d.Headers["Note"] != "a: b" {
+		t.Errorf("value with \": \": decoder = %+v, %v", d, err)
+	}
+}
+
+// TestArmorErrors verifies that damaged armor blocks are reported.
+func TestArmorErrors(t *testing.T) {
+	text := armor(t, "MSG", nil, []byte("Hello, World! This is an armored message."))
+	lines := strings.SplitAfter(text, "\n")
+	body := lines[1]
+
+	tests := []struct {
+		name string
+		text string
+		want error
+	}{
+		{"no begin", "just some text\n", ErrArmorNotFound},
+		{"no end", lines[0] + body, ErrArmorTruncated},
+		{"short body", lines[0] + body[:25] + "\n" + lines[2], ErrArmorLength},
+		{"bad char", lines[0] + strings.Replace(body, body[:1], string(encByte((decTable[body[0]]+1)%85)), 1) + lines[2], ErrArmorChecksum},
+		{"label", lines[0] + body + strings.Replace(lines[2], "MSG", "OTHER", 1), ErrArmorLabel},
+		{"malformed", lines[0] + body + "-----END R85 MSG-----\n", ErrArmorMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewArmorDecoder(strings.NewReader(tt.text))
+			if err == nil {
+				_, err = io.ReadAll(d)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
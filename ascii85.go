@@ -0,0 +1,18 @@
+package r85
+
+// ascii85Alphabet is the Ascii85 alphabet: '!' (33) through 'u' (117).
+const ascii85Alphabet = "!\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstu"
+
+// Ascii85Encoding is the Ascii85 encoding used by btoa, PostScript and
+// PDF, and by Go's encoding/ascii85: the alphabet '!' through 'u', 'z'
+// for an all-zero block, and [PartialTruncate] partial blocks.
+var Ascii85Encoding = NewEncoding(ascii85Alphabet).WithPartial(PartialTruncate).WithZero('z')
+
+// BtoaEncoding is the btoa 4.2 variant of [Ascii85Encoding], which also
+// uses 'y' for a block of four spaces.
+var BtoaEncoding = Ascii85Encoding.WithSpaces('y')
+
+// AdobeEncoding is [Ascii85Encoding] framed by Adobe's "<~" and "~>"
+// delimiters.  When decoding, the "<~" is optional, as in PostScript
+// filters and PDF streams, but the "~>" end-of-data marker is required.
+var AdobeEncoding = Ascii85Encoding.WithFraming("<~", "~>")
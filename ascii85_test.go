@@ -0,0 +1,125 @@
+package r85
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestAscii85MatchesStdlib compares Ascii85Encoding with encoding/ascii85.
+func TestAscii85MatchesStdlib(t *testing.T) {
+	for n := range 300 {
+		src := makeSrc(n)
+		// Include some all-zero blocks to exercise the 'z' shortcut.
+		for i := 8; i+4 <= n; i += 36 {
+			copy(src[i:i+4], []byte{0, 0, 0, 0})
+		}
+		want := make([]byte, ascii85.MaxEncodedLen(n))
+		want = want[:ascii85.Encode(want, src)]
+		got := Ascii85Encoding.EncodeToString(src)
+		if got != string(want) {
+			t.Fatalf("Encode(%d bytes) = %q, want %q", n, got, want)
+		}
+		dec, err := Ascii85Encoding.DecodeString(got)
+		if err != nil {
+			t.Fatalf("Decode(%d bytes): err = %v", n, err)
+		}
+		if !bytes.Equal(dec, src) {
+			t.Fatalf("Decode(%d bytes): roundtrip mismatch", n)
+		}
+	}
+}
+
+// TestAscii85KnownValues tests the Ascii85 profiles against known encodings.
+func TestAscii85KnownValues(t *testing.T) {
+	tests := []struct {
+		enc  *Encoding
+		in   string
+		want string
+	}{
+		{Ascii85Encoding, "Man ", "9jqo^"},
+		{Ascii85Encoding, "Man", "9jqo"},
+		{Ascii85Encoding, "\x00\x00\x00\x00\x00", "z!!"},
+		{Ascii85Encoding, "    ", "+<VdL"},
+		{BtoaEncoding, "        .", "yy/c"},
+		{AdobeEncoding, "Man is ", "<~9jqo^BlbB~>"},
+		{AdobeEncoding, "", "<~~>"},
+	}
+	for _, tt := range tests {
+		got := tt.enc.EncodeToString([]byte(tt.in))
+		if got != tt.want {
+			t.Errorf("Encode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		dec, err := tt.enc.DecodeString(tt.want)
+		if err != nil || string(dec) != tt.in {
+			t.Errorf("Decode(%q) = %q, %v, want %q", tt.want, dec, err, tt.in)
+		}
+	}
+}
+
+// TestAdobeFraming tests decoding of Adobe-framed text.
+func TestAdobeFraming(t *testing.T) {
+	dst := make([]byte, 16)
+	src := []byte("  <~9jqo^\nBlbD~>trailing")
+	ndst, nsrc, err := AdobeEncoding.Decode(dst, src)
+	if err != nil || string(dst[:ndst]) != "Man is " || nsrc != len(src)-len("trailing") {
+		t.Errorf("Decode = %q, %d, %v", dst[:ndst], nsrc, err)
+	}
+
+	// The opening delimiter is optional.
+	got, err := AdobeEncoding.DecodeString("9jqo^BlbD~>")
+	if err != nil || string(got) != "Man is " {
+		t.Errorf("Decode without <~ = %q, %v", got, err)
+	}
+
+	// The closing delimiter is required.
+	if _, err := AdobeEncoding.DecodeString("<~9jqo^BlbD"); err == nil {
+		t.Error("Decode without ~>: expected error, got nil")
+	}
+
+	// A 'z' inside a block is an error.
+	if _, err := Ascii85Encoding.DecodeString("9jzqo^"); err == nil {
+		t.Error("Decode with misplaced z: expected error, got nil")
+	}
+}
+
+// TestAscii85Streaming tests the streaming encoder and decoder of the
+// Ascii85 profiles.
+func TestAscii85Streaming(t *testing.T) {
+	src := makeSrc(1000)
+	copy(src[100:120], make([]byte, 20))
+	copy(src[200:216], "                ")
+	for _, enc := range []*Encoding{Ascii85Encoding, BtoaEncoding, AdobeEncoding} {
+		var buf bytes.Buffer
+		w := enc.NewEncoder(&buf)
+		for i := 0; i < len(src); i += 7 {
+			w.Write(src[i:min(i+7, len(src))])
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close: err = %v", err)
+		}
+		if want := enc.EncodeToString(src); buf.String() != want {
+			t.Fatalf("streaming encoding differs from Encode")
+		}
+
+		text := strings.ReplaceAll(buf.String(), "z", "z\n") + "ignored"
+		got, err := io.ReadAll(enc.NewDecoder(iotest.OneByteReader(strings.NewReader(text))))
+		if enc == AdobeEncoding {
+			if err != nil || !bytes.Equal(got, src) {
+				t.Errorf("Adobe streaming decode: err = %v, match = %v", err, bytes.Equal(got, src))
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("streaming decode: err = %v", err)
+		}
+		// The "ignored" suffix decodes as data without framing, so
+		// compare only the prefix.
+		if !bytes.HasPrefix(got, src) {
+			t.Errorf("streaming decode mismatch")
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package r85
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Conversions of whole numbers use divide and conquer: a number of 2^(k+1)
+// digits is split into two halves of 2^k digits by dividing by 85^(2^k).
+// Together with math/big's subquadratic multiplication and division,
+// this keeps conversion of large numbers fast.
+
+// smallDigits is the number of digits converted with uint64 arithmetic.
+// 85^8 < 2^64.
+const smallDigits = 8
+
+// EncodeBigInt returns x as a single base-85 number using the r85
+// alphabet, without leading zero digits.  It panics if x is negative.
+func EncodeBigInt(x *big.Int) string {
+	return StdEncoding.EncodeBigInt(x)
+}
+
+// DecodeBigInt parses a base-85 number written by [EncodeBigInt].
+func DecodeBigInt(s string) (*big.Int, error) {
+	return StdEncoding.DecodeBigInt(s)
+}
+
+// EncodeBigBytes returns src as a single big-endian base-85 number using
+// the r85 alphabet, with one zero digit for each leading zero byte, in
+// the manner of Bitcoin's base58.
+func EncodeBigBytes(src []byte) string {
+	return StdEncoding.EncodeBigBytes(src)
+}
+
+// DecodeBigBytes parses a number written by [EncodeBigBytes].
+func DecodeBigBytes(s string) ([]byte, error) {
+	return StdEncoding.DecodeBigBytes(s)
+}
+
+// EncodeBigInt returns x as a single base-85 number using enc's alphabet,
+// most significant digit first.  Zero is one zero digit.  Unlike Encode,
+// which encodes each 32-bit block separately, the whole number is
+// converted, so enc's partial-block rule, shortcuts, framing and grouping
+// are not used.  EncodeBigInt panics if x is negative.
+func (enc *Encoding) EncodeBigInt(x *big.Int) string {
+	if x.Sign() < 0 {
+		panic("r85: negative big.Int")
+	}
+	return string(enc.appendBig(nil, x))
+}
+
+// DecodeBigInt parses a base-85 number written by enc.EncodeBigInt.  s
+// must be a non-empty string of digits of enc's alphabet; leading zero
+// digits are allowed.
+func (enc *Encoding) DecodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, CorruptInputError{Reason: "empty number"}
+	}
+	if err := enc.checkDigits(s); err != nil {
+		return nil, err
+	}
+	return enc.parseBig(s, nil), nil
+}
+
+// EncodeBigBytes returns src as a single big-endian base-85 number using
+// enc's alphabet.  Each leading zero byte of src becomes a leading zero
+// digit, so that the length of src is preserved; an empty src is encoded
+// as an empty string.
+func (enc *Encoding) EncodeBigBytes(src []byte) string {
+	z := 0
+	for z < len(src) && src[z] == 0 {
+		z++
+	}
+	dst := make([]byte, z, z+MaxEncodedLen(len(src)-z)+1)
+	for i := range dst {
+		dst[i] = enc.encode[0]
+	}
+	if z < len(src) {
+		dst = enc.appendBig(dst, new(big.Int).SetBytes(src[z:]))
+	}
+	return string(dst)
+}
+
+// DecodeBigBytes parses a number written by enc.EncodeBigBytes.  Each
+// leading zero digit of s becomes a leading zero byte.
+func (enc *Encoding) DecodeBigBytes(s string) ([]byte, error) {
+	if err := enc.checkDigits(s); err != nil {
+		return nil, err
+	}
+	z := 0
+	for z < len(s) && s[z] == enc.encode[0] {
+		z++
+	}
+	dst := make([]byte, z)
+	if z < len(s) {
+		dst = append(dst, enc.parseBig(s[z:], nil).Bytes()...)
+	}
+	return dst, nil
+}
+
+// checkDigits reports a CorruptInputError if s has a byte that is not a
+// digit of enc's alphabet.
+func (enc *Encoding) checkDigits(s string) error {
+	for i := range len(s) {
+		if enc.decodeMap[s[i]] >= 85 {
+			return CorruptInputError{Reason: "invalid character in number"}
+		}
+	}
+	return nil
+}
+
+// bigPowers returns 85^(2^k) for k = 0, 1, ..., up to and including the
+// first power that is greater than x, and at least up to 85^smallDigits.
+func bigPowers(x *big.Int) []*big.Int {
+	pows := []*big.Int{big.NewInt(85)}
+	for k := 0; (1<<k) < smallDigits || pows[k].Cmp(x) <= 0; k++ {
+		pows = append(pows, new(big.Int).Mul(pows[k], pows[k]))
+	}
+	return pows
+}
+
+// appendBig appends the digits of x to dst, without leading zeros.
+func (enc *Encoding) appendBig(dst []byte, x *big.Int) []byte {
+	pows := bigPowers(x)
+	// x < 85^(2^k), so it has at most 2^k digits.
+	k := len(pows) - 1
+	start := len(dst)
+	dst = enc.appendBigWidth(dst, x, pows, k)
+	i := start
+	for i < len(dst)-1 && dst[i] == enc.encode[0] {
+		i++
+	}
+	return append(dst[:start], dst[i:]...)
+}
+
+// appendBigWidth appends exactly 2^k digits of x, which must be less than
+// 85^(2^k), to dst.
+func (enc *Encoding) appendBigWidth(dst []byte, x *big.Int, pows []*big.Int, k int) []byte {
+	if 1<<k <= smallDigits {
+		v := x.Uint64()
+		n := 1 << k
+		dst = append(dst, make([]byte, n)...)
+		for i := len(dst) - 1; i >= len(dst)-n; i-- {
+			dst[i] = enc.encode[v%85]
+			v /= 85
+		}
+		return dst
+	}
+	q, r := new(big.Int).QuoRem(x, pows[k-1], new(big.Int))
+	dst = enc.appendBigWidth(dst, q, pows, k-1)
+	return enc.appendBigWidth(dst, r, pows, k-1)
+}
+
+// parseBig returns the value of the digits in s, which must all be
+// digits of enc's alphabet.  pows holds the powers 85^(2^k) computed
+// so far.
+func (enc *Encoding) parseBig(s string, pows []*big.Int) *big.Int {
+	if len(s) <= smallDigits {
+		var v uint64
+		for i := range len(s) {
+			v = v*85 + uint64(enc.decodeMap[s[i]])
+		}
+		return new(big.Int).SetUint64(v)
+	}
+	// Split off the low 2^k digits, where 2^k is the largest power of two
+	// less than len(s).
+	k := bits.Len(uint(len(s)-1)) - 1
+	for len(pows) <= k {
+		if len(pows) == 0 {
+			pows = append(pows, big.NewInt(85))
+		} else {
+			p := pows[len(pows)-1]
+			pows = append(pows, new(big.Int).Mul(p, p))
+		}
+	}
+	mid := len(s) - 1<<k
+	hi := enc.parseBig(s[:mid], pows)
+	lo := enc.parseBig(s[mid:], pows)
+	return hi.Mul(hi, pows[k]).Add(hi, lo)
+}
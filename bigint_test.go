@@ -0,0 +1,112 @@
+package r85
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// naiveBigInt converts x digit by digit, for comparison.
+func naiveBigInt(x *big.Int) string {
+	if x.Sign() == 0 {
+		return "("
+	}
+	var digits []byte
+	x = new(big.Int).Set(x)
+	r := new(big.Int)
+	for x.Sign() > 0 {
+		x.QuoRem(x, big.NewInt(85), r)
+		digits = append(digits, encTable[r.Int64()])
+	}
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+	return string(digits)
+}
+
+// TestBigIntRoundtrip compares EncodeBigInt with digit-by-digit
+// conversion for numbers of many sizes.
+func TestBigIntRoundtrip(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	for _, n := range []int{0, 1, 7, 8, 9, 63, 64, 65, 200, 1000, 4096} {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte(rng.Uint32())
+		}
+		x := new(big.Int).SetBytes(b)
+		got := EncodeBigInt(x)
+		if want := naiveBigInt(x); got != want {
+			t.Fatalf("%d bytes: EncodeBigInt = %q, want %q", n, got, want)
+		}
+		y, err := DecodeBigInt(got)
+		if err != nil || y.Cmp(x) != 0 {
+			t.Fatalf("%d bytes: DecodeBigInt = %v, %v", n, y, err)
+		}
+	}
+}
+
+// TestBigIntKnownValues tests EncodeBigInt and DecodeBigInt against
+// known values.
+func TestBigIntKnownValues(t *testing.T) {
+	tests := []struct {
+		x    int64
+		want string
+	}{
+		{0, "("},
+		{1, ")"},
+		{84, "|"},
+		{85, ")("},
+		{85*85 - 1, "||"},
+	}
+	for _, tt := range tests {
+		if got := EncodeBigInt(big.NewInt(tt.x)); got != tt.want {
+			t.Errorf("EncodeBigInt(%d) = %q, want %q", tt.x, got, tt.want)
+		}
+	}
+	if x, err := DecodeBigInt("((()"); err != nil || x.Int64() != 1 {
+		t.Errorf("DecodeBigInt with leading zeros = %v, %v", x, err)
+	}
+	for _, s := range []string{"", "( )", "!"} {
+		if _, err := DecodeBigInt(s); err == nil {
+			t.Errorf("DecodeBigInt(%q): expected error", s)
+		}
+	}
+	// RFC 1924's example, as a whole number.
+	x, _ := new(big.Int).SetString("108000000000000000080800200c417a", 16)
+	if got := RFC1924Encoding.EncodeBigInt(x); got != "4)+k&C#VzJ4br>0wv%Yp" {
+		t.Errorf("RFC1924 EncodeBigInt = %q", got)
+	}
+}
+
+// TestBigBytesLeadingZeros verifies that EncodeBigBytes preserves
+// leading zero bytes.
+func TestBigBytesLeadingZeros(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want string
+	}{
+		{nil, ""},
+		{[]byte{0}, "("},
+		{[]byte{0, 0, 1}, "(()"},
+		{[]byte{0, 85}, "()("},
+	}
+	for _, tt := range tests {
+		got := EncodeBigBytes(tt.in)
+		if got != tt.want {
+			t.Errorf("EncodeBigBytes(%x) = %q, want %q", tt.in, got, tt.want)
+		}
+		dec, err := DecodeBigBytes(got)
+		if err != nil || !bytes.Equal(dec, tt.in) {
+			t.Errorf("DecodeBigBytes(%q) = %x, %v, want %x", got, dec, err, tt.in)
+		}
+	}
+	src := append(make([]byte, 5), makeSrc(300)...)
+	if dec, err := DecodeBigBytes(EncodeBigBytes(src)); err != nil || !bytes.Equal(dec, src) {
+		t.Errorf("roundtrip of 305 bytes failed: %v", err)
+	}
+	if dec, err := DecodeBigBytes(strings.Repeat("(", 3)); err != nil || len(dec) != 3 {
+		t.Errorf("DecodeBigBytes(\"(((\") = %x, %v", dec, err)
+	}
+}
@@ -0,0 +1,99 @@
+package r85
+
+import "strconv"
+
+// The check character is computed with the quasigroup x∘y = (2x + y) mod
+// 85, in the style of the Damm algorithm: the interim value starts at 0
+// and is combined with each digit in turn, and the check digit brings the
+// final interim value to 0.  Because 2 is invertible mod 85, changing any
+// one digit changes the result, and so does swapping two adjacent
+// digits a and b, which changes it by (a-b) times a power of 2.
+
+// ChecksumError is returned by [DecodeChecked] when the check character
+// does not match the rest of the text.
+type ChecksumError struct {
+	// Offset is the index in the source of the check character.
+	Offset int
+}
+
+func (e ChecksumError) Error() string {
+	return "r85: check character mismatch at offset " + strconv.Itoa(e.Offset)
+}
+
+// EncodeChecked encodes src like [Encode], followed by a check character.
+func EncodeChecked(dst, src []byte) int {
+	return StdEncoding.EncodeChecked(dst, src)
+}
+
+// DecodeChecked verifies and removes the check character written by
+// [EncodeChecked], and decodes the rest of src like [Decode].
+func DecodeChecked(dst, src []byte) (ndst, nsrc int, err error) {
+	return StdEncoding.DecodeChecked(dst, src)
+}
+
+// EncodeChecked encodes src like [Encoding.Encode], and appends a check
+// character to the digits, before any framing suffix.  The check
+// character detects any single-character error and any transposition of
+// adjacent characters.  dst needs room for enc.MaxEncodedLen(len(src))+1
+// bytes, plus one separator if enc groups its output; if dst is too
+// short, EncodeChecked fills dst and returns len(dst).
+// Shortcut characters are not covered by the check.  Like Encode,
+// EncodeChecked panics unless [Encoding.CheckEncodeLen] accepts
+// len(src).
+func (enc *Encoding) EncodeChecked(dst, src []byte) int {
+	if err := enc.CheckEncodeLen(len(src)); err != nil {
+		panic(err)
+	}
+	di := copy(dst, enc.prefix)
+	if di < len(enc.prefix) {
+		return di
+	}
+	n := enc.encodeBlocks(dst[di:], src)
+	if di+n == len(dst) {
+		return len(dst)
+	}
+	dst[di+n] = enc.encode[enc.checkDigit(dst[di:di+n])]
+	di += enc.group(dst[di:], n+1)
+	return di + copy(dst[di:], enc.suffix)
+}
+
+// DecodeChecked verifies the check character written by
+// [Encoding.EncodeChecked], which is the last digit of src, and decodes
+// the digits before it like [Encoding.Decode].  It returns a
+// [ChecksumError] if the check fails, and a [CorruptInputError] if src
+// has no digits.  Nothing is decoded unless the check succeeds.
+func (enc *Encoding) DecodeChecked(dst, src []byte) (ndst, nsrc int, err error) {
+	start, end := 0, len(src)
+	if enc.suffix != "" {
+		if start, end = enc.frame(src); end < 0 {
+			return 0, 0, CorruptInputError{Reason: "missing end delimiter"}
+		}
+	}
+	ci := end - 1
+	for ci >= start && enc.decodeMap[src[ci]] >= 85 {
+		ci--
+	}
+	if ci < start {
+		return 0, 0, CorruptInputError{Reason: "missing check character"}
+	}
+	if enc.checkDigit(src[start:ci+1]) != 0 {
+		return 0, 0, ChecksumError{ci}
+	}
+	ndst, nsrc, err = enc.decodeBlocks(dst, src[start:ci])
+	if err != nil || start+nsrc < ci {
+		return ndst, start + nsrc, shiftOffset(err, start)
+	}
+	return ndst, end + len(enc.suffix), nil
+}
+
+// checkDigit returns the digit that completes the check of the digits
+// in text, which is 0 if text already ends with a valid check digit.
+func (enc *Encoding) checkDigit(text []byte) byte {
+	interim := 0
+	for _, c := range text {
+		if v := enc.decodeMap[c]; v < 85 {
+			interim = (2*interim + int(v)) % 85
+		}
+	}
+	return byte((85 - 2*interim%85) % 85)
+}
@@ -0,0 +1,70 @@
+package r85
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestCheckedRoundtrip verifies that DecodeChecked accepts the output
+// of EncodeChecked for several profiles.
+func TestCheckedRoundtrip(t *testing.T) {
+	for _, enc := range []*Encoding{StdEncoding, AdobeEncoding, Z85PaddedEncoding, R85ZEncoding} {
+		for n := range 200 {
+			src := makeSrc(n)
+			text := make([]byte, enc.MaxEncodedLen(n)+1)
+			text = text[:enc.EncodeChecked(text, src)]
+			if want := enc.EncodeToString(src); len(text) != len(want)+1 {
+				t.Fatalf("n=%d: EncodeChecked = %q, Encode = %q", n, text, want)
+			}
+			dst := make([]byte, enc.MaxDecodedLen(len(text)))
+			ndst, nsrc, err := enc.DecodeChecked(dst, text)
+			if err != nil || nsrc != len(text) || !bytes.Equal(dst[:ndst], src) {
+				t.Fatalf("n=%d: DecodeChecked(%q) = %x, %d, %v", n, text, dst[:ndst], nsrc, err)
+			}
+		}
+	}
+}
+
+// TestCheckedDetectsErrors verifies that every single-character error
+// and every adjacent transposition is reported as a ChecksumError.
+func TestCheckedDetectsErrors(t *testing.T) {
+	src := []byte("\x12\x34\x56\x78\x9a\xbc\xde\xf0\x11\x22")
+	text := make([]byte, MaxEncodedLen(len(src))+1)
+	text = text[:EncodeChecked(text, src)]
+	dst := make([]byte, len(src))
+	for i := range text {
+		for _, c := range encTable {
+			if c == text[i] {
+				continue
+			}
+			bad := bytes.Clone(text)
+			bad[i] = c
+			if _, _, err := DecodeChecked(dst, bad); !errors.As(err, new(ChecksumError)) {
+				t.Fatalf("substitution %q: err = %v", bad, err)
+			}
+		}
+		if i+1 < len(text) && text[i] != text[i+1] {
+			bad := bytes.Clone(text)
+			bad[i], bad[i+1] = bad[i+1], bad[i]
+			if _, _, err := DecodeChecked(dst, bad); !errors.As(err, new(ChecksumError)) {
+				t.Fatalf("transposition %q: err = %v", bad, err)
+			}
+		}
+	}
+}
+
+// TestCheckedErrors tests DecodeChecked on input without a check digit.
+func TestCheckedErrors(t *testing.T) {
+	var dst [8]byte
+	if _, _, err := DecodeChecked(dst[:], []byte(" \n")); !errors.As(err, new(CorruptInputError)) {
+		t.Errorf("empty input: err = %v", err)
+	}
+	_, _, err := DecodeChecked(dst[:], []byte("(((((*"))
+	if want := (ChecksumError{5}); err != want {
+		t.Errorf("err = %v, want %v", err, want)
+	}
+	if got := err.Error(); got != "r85: check character mismatch at offset 5" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -0,0 +1,13 @@
+package r85
+
+// cookieAlphabet holds, in ASCII order, the 90 cookie-octet characters
+// of RFC 6265 except '%', '&', the apostrophe, '<' and '>'.  Leaving
+// out '<', '>' and '&' keeps encoding/json from escaping them, and
+// leaving out '%' avoids confusion with percent-encoding.
+const cookieAlphabet = "!#$()*+-./0123456789:=?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
+// CookieEncoding uses an alphabet that can be placed in an RFC 6265
+// cookie value, an HTTP header such as Authorization, or a JSON string
+// without quoting or escaping: it has no whitespace, '"', '\\', ',' or
+// ';'.  Like [SortableEncoding], its digit values are in ASCII order.
+var CookieEncoding = NewEncoding(cookieAlphabet)
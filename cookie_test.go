@@ -0,0 +1,47 @@
+package r85
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// TestCookieAlphabet verifies that every character of CookieEncoding
+// is a cookie-octet and survives JSON without escaping.
+func TestCookieAlphabet(t *testing.T) {
+	for i, c := range []byte(cookieAlphabet) {
+		octet := c == 0x21 || (0x23 <= c && c <= 0x2B) || (0x2D <= c && c <= 0x3A) ||
+			(0x3C <= c && c <= 0x5B) || (0x5D <= c && c <= 0x7E)
+		if !octet {
+			t.Errorf("%q is not a cookie-octet", c)
+		}
+		if i > 0 && c <= cookieAlphabet[i-1] {
+			t.Errorf("%q is out of order", c)
+		}
+	}
+	js, _ := json.Marshal(cookieAlphabet)
+	if string(js) != `"`+cookieAlphabet+`"` {
+		t.Errorf("json.Marshal escaped the alphabet: %s", js)
+	}
+}
+
+// TestCookieRoundtrip sends an encoded token through net/http's cookie
+// handling.
+func TestCookieRoundtrip(t *testing.T) {
+	src := makeSrc(300)
+	token := CookieEncoding.EncodeToString(src)
+	c := &http.Cookie{Name: "session", Value: token}
+	if got := c.String(); got != "session="+token {
+		t.Fatalf("cookie was altered: %q", got)
+	}
+	req := &http.Request{Header: http.Header{"Cookie": {c.String()}}}
+	rc, err := req.Cookie("session")
+	if err != nil {
+		t.Fatalf("req.Cookie: err = %v", err)
+	}
+	got, err := CookieEncoding.DecodeString(rc.Value)
+	if err != nil || !bytes.Equal(got, src) {
+		t.Errorf("roundtrip: err = %v", err)
+	}
+}
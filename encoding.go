@@ -0,0 +1,395 @@
+package r85
+
+import "io"
+
+// An Encoding is a radix-85 encoding scheme, defined by an 85-character
+// alphabet, a rule for partial blocks, optional shortcut characters and
+// optional framing.  [StdEncoding] is the r85 encoding used by the
+// package-level functions; other profiles are derived from it or built
+// with [NewEncoding].
+//
+// Whatever the alphabet, full blocks are encoded with the same SIMD
+// kernels as r85, which look up the characters of other alphabets in
+// tables.
+type Encoding struct {
+	encode    [85]byte
+	decodeMap [256]byte // digit value, or one of the digit* markers
+	toR85     [256]byte // maps a digit of this alphabet to its r85 character, or 0
+	toRows    [128]byte // toR85 for bytes below 0x80, laid out by remapRows
+	encRows   [96]byte  // encode, padded and laid out likewise
+	remap     bool      // whether the alphabet differs from r85
+	partial   PartialMode
+	zero      byte // shortcut for an all-zero block, or 0 if none
+	zeroRun   byte // marker for a run of all-zero blocks, or 0 if none
+	spaces    byte // shortcut for a block of four spaces, or 0 if none
+	segment   byte // delimiter between segments of a stream, or 0 if none
+	prefix    string
+	suffix    string
+	groups    int    // number of characters per group, or 0 if not grouped
+	sep       string // separator between groups
+}
+
+// Markers in Encoding.decodeMap for bytes that are not digits.
+const (
+	digitSegment  = 0xFA // the delimiter between segments
+	digitZeroRun  = 0xFB // the marker for a run of all-zero blocks
+	digitSpaces   = 0xFC // the shortcut for a block of four spaces
+	digitZero     = 0xFD // the shortcut for an all-zero block
+	digitReserved = 0xFE // reserved for a shortcut; an error when decoding
+	digitSkip     = 0xFF // not part of the encoding; skipped when decoding
+)
+
+// A zero run marker is followed by one digit d, and stands for
+// d+minZeroRun all-zero blocks.
+const (
+	minZeroRun = 3
+	maxZeroRun = minZeroRun + 84
+)
+
+// PartialMode selects how an [Encoding] handles a final block of 1–3 bytes.
+type PartialMode int
+
+const (
+	// PartialValue encodes the 8-, 16- or 24-bit value of the final block
+	// in 2, 3 or 4 characters.  This is the r85 rule.
+	PartialValue PartialMode = iota
+	// PartialTruncate pads the final block of n bytes with zero bytes,
+	// encodes it, and keeps the first n+1 characters.  Decoding pads the
+	// characters with the highest digit and keeps the first n bytes.
+	// This is the Ascii85 rule.
+	PartialTruncate
+	// PartialNone allows only full blocks: the input to Encode must be a
+	// multiple of 4 bytes, and Decode reports a CorruptInputError unless
+	// the number of digits is a multiple of 5.  This is the Z85 rule.
+	PartialNone
+	// PartialZeroPad pads the final block with zero bytes and encodes all
+	// 5 characters.  Decoding cannot tell the padding from data, so it
+	// returns the padding as zero bytes; a short final block is decoded
+	// as for PartialTruncate.  This is the rule of git's base85 and of
+	// Python's base64.b85encode with pad=True.
+	PartialZeroPad
+	// PartialPadded encodes the final block of n bytes, with value v, as a
+	// full 5-character block with the value 2^32 + (n-1)*2^24 + v, which
+	// no full block can have.  Output is always a multiple of 5
+	// characters, and decoding recovers the exact length.  Only skipped
+	// characters may follow a padded block.
+	PartialPadded
+)
+
+// StdEncoding is the r85 encoding.  It also accepts '<' and '`' when
+// decoding, as aliases for '}' and '~', and reports the r85z shortcut
+// characters '!' and '#', and so the segment delimiter of
+// [SegmentedEncoding], as corrupt input rather than skipping them.
+var StdEncoding = newStdEncoding()
+
+// PaddedEncoding is r85 with [PartialPadded], for consumers that need
+// output in whole 5-character groups.  Its output for inputs that are a
+// multiple of 4 bytes is plain r85.
+var PaddedEncoding = StdEncoding.WithPartial(PartialPadded)
+
+// SegmentedEncoding is r85 with '!' as the segment delimiter, for
+// long-lived streams whose encoder must be able to flush every byte: see
+// [Encoding.WithSegments].  '!' is reserved in [StdEncoding], so plain
+// decoders report segmented text as corrupt rather than misreading it.
+var SegmentedEncoding = StdEncoding.WithSegments('!')
+
+func newStdEncoding() *Encoding {
+	enc := NewEncoding(string(encTable[:]))
+	enc.decodeMap = decTable
+	return enc
+}
+
+// NewEncoding returns a new Encoding defined by the given alphabet, which
+// must contain 85 distinct bytes, in digit order.  The new encoding uses
+// [PartialValue], no shortcuts and no framing.
+func NewEncoding(alphabet string) *Encoding {
+	if len(alphabet) != 85 {
+		panic("r85: encoding alphabet is not 85 bytes long")
+	}
+	enc := &Encoding{}
+	for i := range enc.decodeMap {
+		enc.decodeMap[i] = digitSkip
+	}
+	for i := range 85 {
+		c := alphabet[i]
+		if enc.decodeMap[c] != digitSkip {
+			panic("r85: encoding alphabet contains duplicate bytes")
+		}
+		enc.encode[i] = c
+		enc.decodeMap[c] = byte(i)
+		enc.toR85[c] = encTable[i]
+		if c != encTable[i] {
+			enc.remap = true
+		}
+	}
+	copy(enc.toRows[:], enc.toR85[:])
+	copy(enc.encRows[:], enc.encode[:])
+	remapRows(enc.toRows[:])
+	remapRows(enc.encRows[:])
+	return enc
+}
+
+// WithPartial creates a new encoding identical to enc except that it
+// handles a final partial block according to mode.
+func (enc Encoding) WithPartial(mode PartialMode) *Encoding {
+	enc.partial = mode
+	return &enc
+}
+
+// WithZero creates a new encoding identical to enc except that the
+// character c stands for an all-zero 4-byte block, like Ascii85's 'z'.
+// The shortcut is never used for a partial block.  A zero c removes the
+// shortcut.  WithZero panics if c is a digit of enc's alphabet.
+func (enc Encoding) WithZero(c byte) *Encoding {
+	enc.setShortcut(&enc.zero, c, digitZero)
+	return &enc
+}
+
+// WithSpaces creates a new encoding identical to enc except that the
+// character c stands for a block of four spaces, like btoa's 'y'.
+// A zero c removes the shortcut.  WithSpaces panics if c is a digit of
+// enc's alphabet.
+func (enc Encoding) WithSpaces(c byte) *Encoding {
+	enc.setShortcut(&enc.spaces, c, digitSpaces)
+	return &enc
+}
+
+// WithZeroRun creates a new encoding identical to enc except that the
+// character c, followed by a digit d, stands for a run of d+3 all-zero
+// 4-byte blocks.  Longer runs are split.  A zero c removes the marker.
+// WithZeroRun panics if c is a digit of enc's alphabet.
+func (enc Encoding) WithZeroRun(c byte) *Encoding {
+	enc.setShortcut(&enc.zeroRun, c, digitZeroRun)
+	return &enc
+}
+
+// WithSegments creates a new encoding identical to enc except that the
+// character c delimits segments of a stream.  The Flush method of an
+// encoder from enc.NewEncoder ends the current segment, with a short
+// final block if need be, and writes c; decoders accept a short final
+// block before each delimiter.  Encode never writes delimiters.  A zero
+// c removes the delimiter.  WithSegments panics if c is a digit of enc's
+// alphabet.
+func (enc Encoding) WithSegments(c byte) *Encoding {
+	enc.setShortcut(&enc.segment, c, digitSegment)
+	return &enc
+}
+
+func (enc *Encoding) setShortcut(field *byte, c, marker byte) {
+	if *field != 0 {
+		enc.decodeMap[*field] = digitSkip
+	}
+	*field = c
+	if c == 0 {
+		return
+	}
+	if v := enc.decodeMap[c]; v != digitSkip && v != digitReserved {
+		panic("r85: shortcut character is already used by the encoding")
+	}
+	enc.decodeMap[c] = marker
+}
+
+// WithFraming creates a new encoding identical to enc except that
+// encoded text begins with prefix and ends with suffix, like Adobe's
+// "<~" and "~>".  When decoding, the prefix is optional but the suffix
+// is required, and input after the suffix is ignored.  The suffix must
+// begin with a byte that is not a digit of enc's alphabet.
+func (enc Encoding) WithFraming(prefix, suffix string) *Encoding {
+	if (prefix != "" && suffix == "") || (suffix != "" && enc.decodeMap[suffix[0]] < 85) {
+		panic("r85: invalid framing suffix")
+	}
+	enc.prefix = prefix
+	enc.suffix = suffix
+	return &enc
+}
+
+// WithGroups creates a new encoding identical to enc except that the
+// encoder writes sep between groups of n characters, such as
+// "xxxxx xxxxx xxxxx xxxxx" for n = 5 and sep = " ".  Framing is not part
+// of any group.  Decoders skip the separators, so grouped text decodes
+// like ungrouped text.  A non-positive n removes the grouping.
+// WithGroups panics if sep contains a digit or shortcut character of
+// enc; note that '-' and '_' are r85 digits.
+func (enc Encoding) WithGroups(n int, sep string) *Encoding {
+	for i := range len(sep) {
+		if enc.decodeMap[sep[i]] != digitSkip {
+			panic("r85: group separator is used by the encoding")
+		}
+	}
+	enc.groups, enc.sep = max(n, 0), sep
+	return &enc
+}
+
+// MaxEncodedLen returns the maximum length of an encoding of n source
+// bytes using enc, including any framing and group separators.
+func (enc *Encoding) MaxEncodedLen(n int) int {
+	if enc.partial == PartialZeroPad || enc.partial == PartialPadded {
+		n = (n + 3) &^ 3
+	}
+	return len(enc.prefix) + enc.groupedLen(MaxEncodedLen(n)) + len(enc.suffix)
+}
+
+// CheckEncodeLen returns [ErrPartialBlock] if enc cannot encode n
+// source bytes: that is, if enc uses [PartialNone] and n is not a
+// multiple of 4.  Check the length of input that may not be whole blocks
+// before passing it to Encode, EncodeToString or EncodeChecked, which
+// panic instead.
+func (enc *Encoding) CheckEncodeLen(n int) error {
+	if enc.partial == PartialNone && n%4 != 0 {
+		return ErrPartialBlock
+	}
+	return nil
+}
+
+// groupedLen returns the length of n characters once grouped.
+func (enc *Encoding) groupedLen(n int) int {
+	if enc.groups == 0 || n == 0 {
+		return n
+	}
+	return n + (n-1)/enc.groups*len(enc.sep)
+}
+
+// group inserts separators into the first n characters of b, in place,
+// and returns the grouped length.  If b is too short, the grouped text
+// is truncated to len(b).
+func (enc *Encoding) group(b []byte, n int) int {
+	if enc.groups == 0 || n <= enc.groups {
+		return n
+	}
+	g, ls := enc.groups, len(enc.sep)
+	// Work backwards, so that each character is read before it is
+	// overwritten.
+	for i := n - 1; i > 0; i-- {
+		j := i + i/g*ls
+		if j < len(b) {
+			b[j] = b[i]
+		}
+		if i%g == 0 {
+			for k := range ls {
+				if p := j - ls + k; p < len(b) {
+					b[p] = enc.sep[k]
+				}
+			}
+		}
+	}
+	return min(enc.groupedLen(n), len(b))
+}
+
+// MaxDecodedLen returns the maximum length of a decoding of n source
+// bytes using enc.  Shortcut characters decode to 4 bytes each, and a
+// 2-character zero run to as many as 348 bytes.
+func (enc *Encoding) MaxDecodedLen(n int) int {
+	switch {
+	case enc.zeroRun != 0:
+		return max(4*n, 4*maxZeroRun*(n/2))
+	case enc.zero != 0 || enc.spaces != 0:
+		return 4 * n
+	}
+	return MaxDecodedLen(n)
+}
+
+// decodedLen returns the maximum length of the decoding of src using
+// enc.  Unlike MaxDecodedLen, it accounts for the shortcuts that src
+// actually contains.
+func (enc *Encoding) decodedLen(src []byte) int {
+	if enc.zero == 0 && enc.zeroRun == 0 && enc.spaces == 0 {
+		return MaxDecodedLen(len(src))
+	}
+	n, digits := 0, 0
+	run := false
+	for _, c := range src {
+		switch v := enc.decodeMap[c]; {
+		case v < 85 && run:
+			n += 4 * (int(v) + minZeroRun)
+			run = false
+		case v < 85:
+			digits++
+		case v == digitZeroRun:
+			run = true
+		case v == digitZero || v == digitSpaces:
+			n += 4
+		}
+	}
+	return n + MaxDecodedLen(digits)
+}
+
+// frameReader strips an optional prefix, after any leading whitespace,
+// from the start of r and reports io.EOF once it has read suffix.
+type frameReader struct {
+	r       io.Reader
+	prefix  string
+	suffix  string
+	matched int  // number of bytes of prefix or suffix matched so far
+	body    bool // whether the prefix has been skipped or ruled out
+	done    bool // whether the suffix has been read
+	buf     [512]byte
+	obuf    []byte
+	out     []byte
+	err     error
+}
+
+func (f *frameReader) Read(p []byte) (int, error) {
+	for len(f.out) == 0 {
+		if f.done {
+			return 0, io.EOF
+		}
+		if f.err != nil {
+			if f.err == io.EOF {
+				return 0, CorruptInputError{Reason: "missing end delimiter"}
+			}
+			return 0, f.err
+		}
+		var n int
+		n, f.err = f.r.Read(f.buf[:])
+		if n == 0 && f.err == nil {
+			// Nothing is available yet.
+			return 0, nil
+		}
+		f.scan(f.buf[:n])
+	}
+	n := copy(p, f.out)
+	f.out = f.out[n:]
+	return n, nil
+}
+
+// scan filters in into f.out.
+func (f *frameReader) scan(in []byte) {
+	out := f.obuf[:0]
+	for _, c := range in {
+		if !f.body {
+			if f.matched < len(f.prefix) && c == f.prefix[f.matched] {
+				f.matched++
+				if f.matched == len(f.prefix) {
+					f.body, f.matched = true, 0
+				}
+				continue
+			}
+			if f.matched == 0 && isSpace(c) {
+				continue
+			}
+			// There is no prefix, so the bytes that looked like one are data.
+			out = append(out, f.prefix[:f.matched]...)
+			f.body, f.matched = true, 0
+		}
+		if c == f.suffix[f.matched] {
+			f.matched++
+			if f.matched == len(f.suffix) {
+				f.done = true
+				break
+			}
+			continue
+		}
+		if f.matched > 0 {
+			// A false start of the suffix.
+			out = append(out, f.suffix[:f.matched]...)
+			f.matched = 0
+			if c == f.suffix[0] {
+				f.matched = 1
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+	f.obuf = out
+	f.out = out
+}
@@ -146,6 +146,29 @@ else
 	pass "extra_args"
 fi
 
+# Test 14: Sequential sortable IDs are in byte order
+out=$("$BIN" -n 100 -seq -uuid v7 -sortable)
+if printf '%s\n' "$out" | LC_ALL=C sort -c 2>/dev/null; then
+	pass "sortable_order"
+else
+	fail "sortable_order" "output is not sorted"
+fi
+
+# Test 15: -group 5 splits a v4 ID into four 5-character groups
+out=$("$BIN" -uuid v4 -group 5)
+if printf '%s' "$out" | grep -Eq '^[(-~]{5}( [(-~]{5}){3}$'; then
+	pass "group_format"
+else
+	fail "group_format" "got '$out'"
+fi
+
+# Test 16: A separator that is an r85 character is rejected
+if "$BIN" -group 5 -sep - 2>/dev/null; then
+	fail "group_bad_sep" "should have exited non-zero"
+else
+	pass "group_bad_sep"
+fi
+
 echo ""
 echo "Results: $PASS passed, $FAIL failed"
 [ "$FAIL" -eq 0 ] || exit 1
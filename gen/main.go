@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/entrope/r85"
@@ -15,6 +16,9 @@ func main() {
 	n := flag.Int("n", 1, "number of IDs to generate")
 	uuidVer := flag.String("uuid", "", "UUID version: v4 or v7 (omit for 96-bit random)")
 	seq := flag.Bool("seq", false, "sequential IDs from a random base")
+	sortable := flag.Bool("sortable", false, "use the sortable alphabet, so IDs sort like their bytes")
+	group := flag.Int("group", 0, "insert -sep between groups of this many characters (0 for none)")
+	sep := flag.String("sep", " ", "group separator; must not be an r85 character such as '-'")
 	flag.Parse()
 
 	if flag.NArg() > 0 {
@@ -25,6 +29,14 @@ func main() {
 		fmt.Fprintf(os.Stderr, "-n must be at least 1\n")
 		os.Exit(2)
 	}
+	if *group < 0 {
+		fmt.Fprintf(os.Stderr, "-group must not be negative\n")
+		os.Exit(2)
+	}
+	if strings.ContainsFunc(*sep, func(r rune) bool { return '(' <= r && r <= '~' || r == '!' || r == '#' }) {
+		fmt.Fprintf(os.Stderr, "-sep must not contain r85 characters: %q\n", *sep)
+		os.Exit(2)
+	}
 
 	var (
 		size int
@@ -51,9 +63,17 @@ func main() {
 		os.Exit(2)
 	}
 
+	enc := r85.StdEncoding
+	if *sortable {
+		enc = r85.SortableEncoding
+	}
+	if *group > 0 {
+		enc = enc.WithGroups(*group, *sep)
+	}
+
 	id := make([]byte, size)
 	gen(id)
-	fmt.Println(r85.EncodeToString(id))
+	fmt.Println(enc.EncodeToString(id))
 
 	for i := 1; i < *n; i++ {
 		if *seq {
@@ -64,7 +84,7 @@ func main() {
 		} else {
 			gen(id)
 		}
-		fmt.Println(r85.EncodeToString(id))
+		fmt.Println(enc.EncodeToString(id))
 	}
 }
 
@@ -0,0 +1,214 @@
+package r85
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// gitLineMax is the most data bytes git puts on one binary patch line.
+const gitLineMax = 52
+
+// NewGitBinaryWriter returns a writer that formats the data written to
+// it as the lines of a git binary patch hunk.  Each line holds up to 52
+// bytes: a length character ('A'–'Z' for 1–26 bytes, 'a'–'z' for 27–52),
+// then the bytes encoded with [RFC1924PadEncoding], then a newline.
+// Close writes the final short line; it does not write the blank line
+// that ends a hunk.  The data is written as is: git expects it to be
+// zlib-compressed, which [WriteGitBinaryHunk] does.
+func NewGitBinaryWriter(w io.Writer) io.WriteCloser {
+	return &gitWriter{w: w}
+}
+
+type gitWriter struct {
+	w   io.Writer
+	buf [gitLineMax]byte
+	n   int
+	err error
+}
+
+func (g *gitWriter) Write(p []byte) (int, error) {
+	if g.err != nil {
+		return 0, g.err
+	}
+	written := 0
+	for len(p) > 0 {
+		k := copy(g.buf[g.n:], p)
+		g.n += k
+		p = p[k:]
+		written += k
+		if g.n == gitLineMax {
+			if g.err = g.flush(); g.err != nil {
+				return written, g.err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (g *gitWriter) flush() error {
+	var line [1 + 5*gitLineMax/4 + 1]byte
+	if g.n <= 26 {
+		line[0] = 'A' + byte(g.n-1)
+	} else {
+		line[0] = 'a' + byte(g.n-27)
+	}
+	n := 1 + RFC1924PadEncoding.Encode(line[1:], g.buf[:g.n])
+	line[n] = '\n'
+	g.n = 0
+	_, err := g.w.Write(line[:n+1])
+	return err
+}
+
+func (g *gitWriter) Close() error {
+	if g.err == nil && g.n > 0 {
+		g.err = g.flush()
+	}
+	return g.err
+}
+
+// NewGitBinaryReader returns a reader that decodes the lines of a git
+// binary patch hunk, as written by [NewGitBinaryWriter].  It stops at a
+// blank line, which it consumes, or at the end of r.  A line whose
+// length does not match its length character, or that has a character
+// outside the alphabet, is reported as a [CorruptInputError].
+func NewGitBinaryReader(r *bufio.Reader) io.Reader {
+	return &gitReader{r: r}
+}
+
+type gitReader struct {
+	r    *bufio.Reader
+	buf  [gitLineMax + 3]byte
+	out  []byte
+	done bool
+}
+
+func (g *gitReader) Read(p []byte) (int, error) {
+	for len(g.out) == 0 {
+		if g.done {
+			return 0, io.EOF
+		}
+		line, err := g.r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			return 0, CorruptInputError{Reason: "git binary patch line too long"}
+		}
+		text := bytes.TrimRight(line, "\r\n")
+		if len(text) == 0 {
+			g.done = true
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
+			continue
+		}
+		var n int
+		switch c := text[0]; {
+		case 'A' <= c && c <= 'Z':
+			n = int(c-'A') + 1
+		case 'a' <= c && c <= 'z':
+			n = int(c-'a') + 27
+		default:
+			return 0, CorruptInputError{Reason: "invalid git binary patch line length"}
+		}
+		if len(text)-1 != 5*((n+3)/4) {
+			return 0, CorruptInputError{Reason: "git binary patch line length mismatch"}
+		}
+		for _, c := range text[1:] {
+			if RFC1924PadEncoding.decodeMap[c] >= 85 {
+				return 0, CorruptInputError{Reason: "invalid character in git binary patch line"}
+			}
+		}
+		ndst, _, derr := RFC1924PadEncoding.Decode(g.buf[:], text[1:])
+		if derr != nil {
+			return 0, derr
+		}
+		if ndst < n {
+			return 0, CorruptInputError{Reason: "git binary patch line length mismatch"}
+		}
+		g.out = g.buf[:n]
+		if err != nil {
+			g.done = true
+		}
+	}
+	n := copy(p, g.out)
+	g.out = g.out[n:]
+	return n, nil
+}
+
+// A GitBinaryHunk is one hunk of a "GIT binary patch": either the literal
+// contents of a file or a git delta against the preimage.
+type GitBinaryHunk struct {
+	// Delta reports whether Data is a git delta ("delta N") rather than
+	// the literal file contents ("literal N").
+	Delta bool
+	// Data is the uncompressed hunk data.  The size in the hunk header
+	// is len(Data).
+	Data []byte
+}
+
+// WriteGitBinaryHunk writes h to w in git's binary patch format: a
+// "literal N" or "delta N" header, the zlib-compressed data as base-85
+// lines, and a blank line.
+func WriteGitBinaryHunk(w io.Writer, h GitBinaryHunk) error {
+	kind := "literal"
+	if h.Delta {
+		kind = "delta"
+	}
+	if _, err := fmt.Fprintf(w, "%s %d\n", kind, len(h.Data)); err != nil {
+		return err
+	}
+	lines := NewGitBinaryWriter(w)
+	zw := zlib.NewWriter(lines)
+	if _, err := zw.Write(h.Data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := lines.Close(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadGitBinaryHunk reads a hunk in git's binary patch format from r,
+// starting at its "literal N" or "delta N" header, and inflates its
+// data.  It reports a [CorruptInputError] if the inflated data is not N
+// bytes long.  On success, r is positioned after the hunk's blank line.
+func ReadGitBinaryHunk(r *bufio.Reader) (GitBinaryHunk, error) {
+	var h GitBinaryHunk
+	header, err := r.ReadString('\n')
+	if err != nil && (err != io.EOF || header == "") {
+		return h, err
+	}
+	kind, size, ok := strings.Cut(strings.TrimRight(header, "\r\n"), " ")
+	n, perr := strconv.ParseInt(size, 10, 64)
+	if !ok || perr != nil || n < 0 || (kind != "literal" && kind != "delta") {
+		return h, CorruptInputError{Reason: "malformed git binary hunk header"}
+	}
+	h.Delta = kind == "delta"
+
+	lines := NewGitBinaryReader(r)
+	zr, err := zlib.NewReader(lines)
+	if err != nil {
+		return h, err
+	}
+	// Read one byte more than expected, to detect overlong data.
+	h.Data, err = io.ReadAll(io.LimitReader(zr, n+1))
+	if err != nil {
+		return h, err
+	}
+	if int64(len(h.Data)) != n {
+		return h, CorruptInputError{Reason: "git binary hunk size mismatch"}
+	}
+	if err = zr.Close(); err != nil {
+		return h, err
+	}
+	// Consume any lines after the end of the zlib stream.
+	_, err = io.Copy(io.Discard, lines)
+	return h, err
+}
@@ -0,0 +1,104 @@
+package r85
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// gitPatch is the output of "git diff --binary" for a 13-byte file
+// replaced by 768 bytes.
+const gitPatch = `GIT binary patch
+literal 768
+zcmZQzWMXDvWn<^y<l^Sx<>MC+6cQE@6%&_` + "`" + `l#-T_m6KOcR8m$^Ra4i{)Y8_` + "`" + `)zddH
+zG%_|ZH8Z!cw6eCbwX=6{baHlab#wRd^z!!c_45x13<?ej4GWKmjEatljf+o6OiE5k
+zO-s+n%*xKm&C4$+EGjN3Ei136tg5c5t*dWnY-(<4ZENr7?CS36?dzW~anj@|Q>RUz
+zF>}` + "`" + `JIdkXDU$Ah|;w4L$Enl&6)#^2C*R9{Mant54TeofBv2)k%J$v` + "`" + `<KXCBS;Uh<n
+z9Y1mM)af&4&z-+;@zUihSFc^aar4&gJ9qEhfAH|p<0ns_J%91?)$2EJ-@X6v@zduo
+XU%!3-@$=X3KY#!IXBhSWh>m{%pkjWI
+
+literal 13
+Ucmc~u&B@7UD9<m-NnvCH03pc)KmY&$
+
+`
+
+// TestReadGitBinaryHunk reads both hunks of a patch produced by git.
+func TestReadGitBinaryHunk(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader(gitPatch))
+	r.ReadString('\n') // "GIT binary patch"
+
+	fwd, err := ReadGitBinaryHunk(r)
+	if err != nil {
+		t.Fatalf("forward hunk: err = %v", err)
+	}
+	want := make([]byte, 768)
+	for i := range want {
+		want[i] = byte(i)
+	}
+	if fwd.Delta || !bytes.Equal(fwd.Data, want) {
+		t.Errorf("forward hunk = %v, %d bytes", fwd.Delta, len(fwd.Data))
+	}
+
+	rev, err := ReadGitBinaryHunk(r)
+	if err != nil {
+		t.Fatalf("reverse hunk: err = %v", err)
+	}
+	if rev.Delta || string(rev.Data) != "hello\x00world\x01\x02" {
+		t.Errorf("reverse hunk = %v, %q", rev.Delta, rev.Data)
+	}
+}
+
+// TestGitBinaryRoundtrip writes and reads back hunks of various sizes.
+func TestGitBinaryRoundtrip(t *testing.T) {
+	for _, n := range []int{0, 1, 26, 27, 52, 53, 1000} {
+		var buf bytes.Buffer
+		h := GitBinaryHunk{Delta: n%2 == 1, Data: makeSrc(n)}
+		if err := WriteGitBinaryHunk(&buf, h); err != nil {
+			t.Fatalf("WriteGitBinaryHunk: err = %v", err)
+		}
+		buf.WriteString("after\n")
+		r := bufio.NewReader(&buf)
+		got, err := ReadGitBinaryHunk(r)
+		if err != nil {
+			t.Fatalf("ReadGitBinaryHunk(%d bytes): err = %v", n, err)
+		}
+		if got.Delta != h.Delta || !bytes.Equal(got.Data, h.Data) {
+			t.Errorf("roundtrip(%d bytes) mismatch", n)
+		}
+		if rest, _ := r.ReadString('\n'); rest != "after\n" {
+			t.Errorf("text after hunk = %q", rest)
+		}
+	}
+}
+
+// TestGitBinaryLines tests the per-line framing.
+func TestGitBinaryLines(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewGitBinaryWriter(&buf)
+	w.Write(makeSrc(60))
+	w.Close()
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 3 || lines[0][0] != 'z' || len(lines[0]) != 66 || lines[1][0] != 'H' || len(lines[1]) != 11 {
+		t.Errorf("lines = %q", lines)
+	}
+
+	bad := []string{"Bxxxx\n", "0abcde\n", "A0000\n"}
+	for _, s := range bad {
+		r := NewGitBinaryReader(bufio.NewReader(strings.NewReader(s)))
+		if _, err := r.Read(make([]byte, 64)); err == nil {
+			t.Errorf("Read(%q): expected error, got nil", s)
+		}
+	}
+	// Spaces in a line of the right length once returned stale bytes
+	// from the line before.
+	text := []byte(buf.String())
+	copy(text[67+6:], "     ")
+	if _, err := io.ReadAll(NewGitBinaryReader(bufio.NewReader(bytes.NewReader(text)))); err == nil {
+		t.Errorf("Read(%q): expected error, got nil", text)
+	}
+	if _, err := ReadGitBinaryHunk(bufio.NewReader(strings.NewReader("literal 14\nUcmc~u&B@7UD9<m-NnvCH03pc)KmY&$\n\n"))); err == nil {
+		t.Error("size mismatch: expected error, got nil")
+	}
+}
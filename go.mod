@@ -2,4 +2,7 @@ module github.com/entrope/r85
 
 go 1.25.6
 
-require golang.org/x/sys v0.41.0
+require (
+	golang.org/x/sys v0.41.0
+	golang.org/x/text v0.41.0
+)
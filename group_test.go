@@ -0,0 +1,70 @@
+package r85
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestGroupsKnownValues tests grouped output against known encodings.
+func TestGroupsKnownValues(t *testing.T) {
+	grouped := StdEncoding.WithGroups(5, " ")
+	tests := []struct {
+		enc  *Encoding
+		in   string
+		want string
+	}{
+		{grouped, "", ""},
+		{grouped, "\x00\x00\x00\x00", "((((("},
+		{grouped, "\x00\x00\x00\x00\x00", "((((( (("},
+		{grouped, strings.Repeat("\x00", 16), "((((( ((((( ((((( ((((("},
+		{StdEncoding.WithGroups(4, "'"), strings.Repeat("\x00", 8), "(((('(((('(("},
+		{AdobeEncoding.WithGroups(2, "  "), "Man ", "<~9j  qo  ^~>"},
+	}
+	for _, tt := range tests {
+		got := tt.enc.EncodeToString([]byte(tt.in))
+		if got != tt.want {
+			t.Errorf("Encode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if len(got) > tt.enc.MaxEncodedLen(len(tt.in)) {
+			t.Errorf("Encode(%q): length %d > MaxEncodedLen %d", tt.in, len(got), tt.enc.MaxEncodedLen(len(tt.in)))
+		}
+		dec, err := tt.enc.DecodeString(got)
+		if err != nil || string(dec) != tt.in {
+			t.Errorf("Decode(%q) = %q, %v, want %q", got, dec, err, tt.in)
+		}
+	}
+}
+
+// TestGroupsStreaming verifies that the streaming encoder groups its
+// output exactly like Encode, whatever the write sizes.
+func TestGroupsStreaming(t *testing.T) {
+	for _, enc := range []*Encoding{StdEncoding.WithGroups(5, " "), AdobeEncoding.WithGroups(7, "\n")} {
+		for _, n := range []int{0, 3, 4, 20, 100, 1000, 5000} {
+			src := makeSrc(n)
+			want := enc.EncodeToString(src)
+			var buf bytes.Buffer
+			w := enc.NewEncoder(&buf)
+			for i := 0; i < n; i += 13 {
+				w.Write(src[i:min(n, i+13)])
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != want {
+				t.Fatalf("n=%d: stream = %q, want %q", n, buf.String(), want)
+			}
+		}
+	}
+}
+
+// TestGroupsInvalidSeparator verifies that separators which are digits
+// are rejected.
+func TestGroupsInvalidSeparator(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithGroups(5, \"-\") did not panic")
+		}
+	}()
+	StdEncoding.WithGroups(5, "-")
+}
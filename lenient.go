@@ -0,0 +1,128 @@
+package r85
+
+import (
+	"io"
+	"strconv"
+)
+
+// Recovery selects what lenient decoding does with a damaged block.
+type Recovery int
+
+const (
+	// RecoverDrop leaves damaged blocks out of the output.
+	RecoverDrop Recovery = iota
+	// RecoverZero writes zero bytes in place of a damaged block: 4 for a
+	// block of 5 digits, or one less than the number of digits for a
+	// block that was cut short.  So the data after a damaged full block
+	// keeps its position.
+	RecoverZero
+)
+
+// CorruptInputErrors lists the damaged blocks found by lenient
+// decoding, in input order.
+type CorruptInputErrors []CorruptInputError
+
+func (e CorruptInputErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return "r85: " + strconv.Itoa(len(e)) + " corrupt blocks, first at offset " +
+		strconv.Itoa(e[0].Offset) + ": " + e[0].Reason
+}
+
+// DecodeLenient decodes src like [Decode], but does not stop at damaged
+// blocks: see [Encoding.DecodeLenient].
+func DecodeLenient(dst, src []byte, mode Recovery) (ndst, nsrc int, err error) {
+	return StdEncoding.DecodeLenient(dst, src, mode)
+}
+
+// DecodeLenient decodes src like [Encoding.Decode], but records each
+// damaged block as a [CorruptInputError], with its offset in src, and
+// carries on after it.  Damaged blocks are dropped or replaced by zero
+// bytes according to mode.  If any block was damaged, err is a
+// [CorruptInputErrors] listing them all, and ndst counts the recovered
+// data.  dst needs room for enc.MaxDecodedLen(len(src)) bytes; if it is
+// too short, DecodeLenient stops when it is full.
+func (enc *Encoding) DecodeLenient(dst, src []byte, mode Recovery) (ndst, nsrc int, err error) {
+	start, end, missing := 0, len(src), false
+	if enc.suffix != "" {
+		if start, end = enc.frame(src); end < 0 {
+			end, missing = len(src), true
+		}
+	}
+	ndst, nsrc, errs := enc.decodeLenient(dst, src[start:end], mode)
+	for i := range errs {
+		errs[i].Offset += start
+	}
+	nsrc += start
+	if nsrc == end && !missing {
+		nsrc += len(enc.suffix)
+	}
+	if missing {
+		errs = append(errs, CorruptInputError{Reason: "missing end delimiter", Offset: len(src)})
+	}
+	if len(errs) > 0 {
+		return ndst, nsrc, errs
+	}
+	return ndst, nsrc, nil
+}
+
+// decodeLenient decodes src into dst without framing, recovering from
+// damaged blocks according to mode.
+func (enc *Encoding) decodeLenient(dst, src []byte, mode Recovery) (ndst, nsrc int, errs CorruptInputErrors) {
+	di, si := 0, 0
+	for si < len(src) {
+		n, m, err := enc.decodeBlocks(dst[di:], src[si:])
+		di += n
+		if err == nil {
+			si += m
+			break
+		}
+		ce := err.(CorruptInputError)
+		ce.Offset += si
+		errs = append(errs, ce)
+		next := si + m
+		if mode == RecoverZero {
+			digits := 0
+			for _, c := range src[ce.Offset:next] {
+				if enc.decodeMap[c] < 85 {
+					digits++
+				}
+			}
+			size := max(digits-1, 0)
+			if digits >= 5 {
+				size = 4
+			}
+			size = min(size, len(dst)-di)
+			clear(dst[di : di+size])
+			di += size
+		}
+		si = next
+	}
+	return di, si, errs
+}
+
+// A DecoderOption configures a decoder made by [NewDecoder] or
+// [Encoding.NewDecoder].
+type DecoderOption func(*decoder)
+
+// WithRecovery makes the decoder lenient, like [Encoding.DecodeLenient]:
+// it drops or zeroes damaged blocks according to mode and keeps going.
+// At the end of its input it reports the damaged blocks as a
+// [CorruptInputErrors] instead of io.EOF, with offsets counted from the
+// start of the stream.
+func WithRecovery(mode Recovery) DecoderOption {
+	return func(d *decoder) {
+		d.lenient, d.mode = true, mode
+	}
+}
+
+// fail records err as the decoder's final error.  A lenient decoder
+// reports the damaged blocks it found in place of io.EOF.
+func (d *decoder) fail(err error) error {
+	if err == io.EOF && len(d.errs) > 0 {
+		err = d.errs
+	}
+	d.err = err
+	return err
+}
@@ -0,0 +1,113 @@
+package r85
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestCorruptInputErrorOffset verifies the offsets reported by Decode.
+func TestCorruptInputErrorOffset(t *testing.T) {
+	tests := []struct {
+		in     string
+		offset int
+	}{
+		{"(((((|||||", 5},
+		{"((((( \n |||||", 8},
+		{"(((((!", 5},
+		{"(((((((!", 5},
+		{"((((((", 5},
+		{"(((((||", 5},
+	}
+	for _, tt := range tests {
+		_, err := DecodeString(tt.in)
+		var ce CorruptInputError
+		if !errors.As(err, &ce) || ce.Offset != tt.offset {
+			t.Errorf("Decode(%q): err = %#v, want offset %d", tt.in, err, tt.offset)
+		}
+	}
+	_, _, err := AdobeEncoding.Decode(make([]byte, 16), []byte("  <~zzuuuuu~>"))
+	if ce, ok := err.(CorruptInputError); !ok || ce.Offset != 6 {
+		t.Errorf("Adobe Decode: err = %#v, want offset 6", err)
+	}
+}
+
+// TestDecodeLenient verifies recovery from damaged blocks in both modes.
+func TestDecodeLenient(t *testing.T) {
+	good := EncodeToString([]byte("abcdefgh"))
+	text := good[:5] + "|||||" + good[5:] + "!" + "((((("
+	want := map[Recovery]string{
+		RecoverDrop: "abcdefgh\x00\x00\x00\x00",
+		RecoverZero: "abcd\x00\x00\x00\x00efgh\x00\x00\x00\x00",
+	}
+	for mode, w := range want {
+		dst := make([]byte, MaxDecodedLen(len(text)))
+		ndst, nsrc, err := DecodeLenient(dst, []byte(text), mode)
+		if string(dst[:ndst]) != w || nsrc != len(text) {
+			t.Errorf("mode %d: DecodeLenient = %q, %d, want %q", mode, dst[:ndst], nsrc, w)
+		}
+		var errs CorruptInputErrors
+		if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Offset != 5 || errs[1].Offset != 15 {
+			t.Errorf("mode %d: err = %#v", mode, err)
+		}
+	}
+
+	dst := make([]byte, 16)
+	if ndst, _, err := DecodeLenient(dst, []byte(good), RecoverDrop); err != nil || string(dst[:ndst]) != "abcdefgh" {
+		t.Errorf("DecodeLenient(undamaged) = %q, %v", dst[:ndst], err)
+	}
+}
+
+// TestCorruptInputErrorsMessage tests the error message of the list.
+func TestCorruptInputErrorsMessage(t *testing.T) {
+	one := CorruptInputErrors{{Reason: "a", Offset: 3}}
+	if got := one.Error(); got != "r85: a" {
+		t.Errorf("Error() = %q", got)
+	}
+	two := append(one, CorruptInputError{Reason: "b", Offset: 9})
+	if got, want := two.Error(), "r85: 2 corrupt blocks, first at offset 3: a"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+// TestDecoderRecovery verifies that a lenient streaming decoder matches
+// DecodeLenient, however its input is split.
+func TestDecoderRecovery(t *testing.T) {
+	src := makeSrc(3000)
+	text := []byte(EncodeToString(src))
+	for _, i := range []int{7, 1500, 2222, 3700} {
+		text[i] = '|'
+		text[i+1] = '|'
+	}
+	text = slices.Insert(text, 2000, '!', '\n', ' ')
+	for _, mode := range []Recovery{RecoverDrop, RecoverZero} {
+		dst := make([]byte, MaxDecodedLen(len(text)))
+		ndst, _, wantErr := DecodeLenient(dst, text, mode)
+		for _, r := range []func(io.Reader) io.Reader{iotest.OneByteReader, iotest.HalfReader, func(r io.Reader) io.Reader { return r }} {
+			got, err := io.ReadAll(NewDecoder(r(bytes.NewReader(text)), WithRecovery(mode)))
+			if !bytes.Equal(got, dst[:ndst]) {
+				t.Fatalf("mode %d: stream decoded %d bytes, want %d", mode, len(got), ndst)
+			}
+			var errs, want CorruptInputErrors
+			errors.As(err, &errs)
+			errors.As(wantErr, &want)
+			if len(want) == 0 || !slices.Equal(errs, want) {
+				t.Fatalf("mode %d: stream errors = %v, want %v", mode, errs, want)
+			}
+		}
+	}
+}
+
+// TestDecoderErrorOffset verifies that a strict streaming decoder
+// reports offsets in the whole stream.
+func TestDecoderErrorOffset(t *testing.T) {
+	text := strings.Repeat("(((((", 500) + "|||||" + "((((("
+	_, err := io.ReadAll(NewDecoder(iotest.HalfReader(strings.NewReader(text))))
+	if ce, ok := err.(CorruptInputError); !ok || ce.Offset != 2500 {
+		t.Errorf("err = %#v, want offset 2500", err)
+	}
+}
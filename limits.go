@@ -0,0 +1,77 @@
+package r85
+
+import (
+	"errors"
+	"io"
+)
+
+// Errors reported by decoders with limits.
+var (
+	ErrDecodedLimit = errors.New("r85: decoded data exceeds limit")
+	ErrSkippedLimit = errors.New("r85: skipped input exceeds limit")
+	ErrSkipRunLimit = errors.New("r85: run of skipped input exceeds limit")
+)
+
+// WithMaxDecoded limits the decoder to n bytes of output.  The decoder
+// returns the first n bytes, and then [ErrDecodedLimit] if there is more.
+func WithMaxDecoded(n int64) DecoderOption {
+	return func(d *decoder) {
+		d.maxDecoded = n
+	}
+}
+
+// WithMaxSkipped limits the decoder to n skipped input bytes in total:
+// bytes that are neither digits nor shortcuts, such as whitespace,
+// counted in the raw input, so including any around the framing.  It
+// reports [ErrSkippedLimit] once more have been read.
+func WithMaxSkipped(n int64) DecoderOption {
+	return func(d *decoder) {
+		d.maxSkipped = n
+	}
+}
+
+// WithMaxSkipRun limits the decoder to runs of at most n consecutive
+// skipped input bytes.  It reports [ErrSkipRunLimit] once a longer run
+// has been read.
+func WithMaxSkipRun(n int) DecoderOption {
+	return func(d *decoder) {
+		d.maxSkipRun = n
+	}
+}
+
+// skipLimiter enforces the skip limits of d on the raw input.  A read
+// that exceeds a limit returns the bytes before the one that exceeded
+// it, along with the error.
+type skipLimiter struct {
+	r io.Reader
+	d *decoder
+}
+
+func (s *skipLimiter) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if i, lerr := s.d.checkSkips(p[:n]); lerr != nil {
+		return i, lerr
+	}
+	return n, err
+}
+
+// checkSkips counts the skipped bytes in the newly read input.  If they
+// exceed the decoder's limits, it returns the index of the byte that
+// exceeded them and an error.
+func (d *decoder) checkSkips(in []byte) (int, error) {
+	for i, c := range in {
+		if d.enc.decodeMap[c] != digitSkip {
+			d.skipRun = 0
+			continue
+		}
+		d.skipped++
+		d.skipRun++
+		if d.maxSkipped >= 0 && d.skipped > d.maxSkipped {
+			return i, ErrSkippedLimit
+		}
+		if d.maxSkipRun >= 0 && d.skipRun > d.maxSkipRun {
+			return i, ErrSkipRunLimit
+		}
+	}
+	return len(in), nil
+}
@@ -0,0 +1,116 @@
+package r85
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestDecoderLimits verifies each limit at and just past its value.
+func TestDecoderLimits(t *testing.T) {
+	src := makeSrc(2000)
+	text := EncodeToString(src)
+	// Skipped bytes: a newline after every 50 characters, and a run of
+	// 10 spaces near the end.
+	var b strings.Builder
+	for i := 0; i < len(text); i += 50 {
+		b.WriteString(text[i:min(len(text), i+50)])
+		b.WriteByte('\n')
+	}
+	spaced := b.String()
+	spaced = spaced[:len(spaced)-20] + strings.Repeat(" ", 10) + spaced[len(spaced)-20:]
+	skipped := int64(len(spaced) - len(text))
+
+	tests := []struct {
+		name string
+		opt  DecoderOption
+		err  error
+	}{
+		{"decoded ok", WithMaxDecoded(2000), nil},
+		{"decoded", WithMaxDecoded(1999), ErrDecodedLimit},
+		{"skipped ok", WithMaxSkipped(skipped), nil},
+		{"skipped", WithMaxSkipped(skipped - 1), ErrSkippedLimit},
+		{"run ok", WithMaxSkipRun(10), nil},
+		{"run", WithMaxSkipRun(9), ErrSkipRunLimit},
+		{"run spans reads", WithMaxSkipRun(9), ErrSkipRunLimit},
+	}
+	for _, tt := range tests {
+		r := io.Reader(strings.NewReader(spaced))
+		if tt.name == "run spans reads" {
+			r = iotest.OneByteReader(r)
+		}
+		got, err := io.ReadAll(NewDecoder(r, tt.opt))
+		if err != tt.err {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.err)
+		}
+		if !bytes.HasPrefix(src, got) {
+			t.Errorf("%s: output is not a prefix of the data", tt.name)
+		}
+		if tt.err == ErrDecodedLimit && len(got) != 1999 {
+			t.Errorf("%s: got %d bytes, want 1999", tt.name, len(got))
+		}
+	}
+}
+
+// TestDecoderLimitJunk verifies that a stream of junk is cut off early.
+func TestDecoderLimitJunk(t *testing.T) {
+	junk := io.LimitReader(iotest.OneByteReader(strings.NewReader(strings.Repeat(" ", 1<<20))), 1<<20)
+	counter := &countingReader{r: junk}
+	_, err := io.ReadAll(NewDecoder(counter, WithMaxSkipped(4096)))
+	if err != ErrSkippedLimit {
+		t.Fatalf("err = %v, want ErrSkippedLimit", err)
+	}
+	if counter.n > 8192 {
+		t.Errorf("read %d bytes of junk before stopping", counter.n)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+// TestDecoderLimitFramed verifies that skipped bytes are counted before
+// the framing is removed, including white space before the prefix.
+func TestDecoderLimitFramed(t *testing.T) {
+	junk := io.MultiReader(strings.NewReader(strings.Repeat(" ", 4<<20)), strings.NewReader("<~z~>"))
+	counter := &countingReader{r: junk}
+	_, err := io.ReadAll(AdobeEncoding.NewDecoder(counter, WithMaxSkipped(100)))
+	if err != ErrSkippedLimit {
+		t.Fatalf("err = %v, want ErrSkippedLimit", err)
+	}
+	if counter.n > 8192 {
+		t.Errorf("read %d bytes of junk before stopping", counter.n)
+	}
+
+	text := AdobeEncoding.EncodeToString(makeSrc(100))
+	got, err := io.ReadAll(AdobeEncoding.NewDecoder(strings.NewReader("  \n"+text), WithMaxSkipRun(3)))
+	if err != nil || !bytes.Equal(got, makeSrc(100)) {
+		t.Errorf("within the limit: got %d bytes, %v", len(got), err)
+	}
+	_, err = io.ReadAll(AdobeEncoding.NewDecoder(strings.NewReader("   \n"+text), WithMaxSkipRun(3)))
+	if err != ErrSkipRunLimit {
+		t.Errorf("err = %v, want ErrSkipRunLimit", err)
+	}
+}
+
+// TestDecoderLimitPrefix verifies that the data before a skip limit is
+// returned before the error, when it arrives in the same read.
+func TestDecoderLimitPrefix(t *testing.T) {
+	src := makeSrc(40)
+	text := EncodeToString(src) + "(((" + strings.Repeat(" ", 10) + "((((((("
+	for _, opt := range []DecoderOption{WithMaxSkipRun(3), WithMaxSkipped(3)} {
+		got, err := io.ReadAll(NewDecoder(strings.NewReader(text), opt))
+		if err != ErrSkipRunLimit && err != ErrSkippedLimit || !bytes.Equal(got, src) {
+			t.Errorf("got %d bytes, %v; want %d bytes and a limit error", len(got), err, len(src))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package r85
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestPaddedKnownValues tests PaddedEncoding against known encodings.
+func TestPaddedKnownValues(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"\x00", "z?^4)"},
+		{"\xff", "z?^7)"},
+		{"\x00\x00", "zZy=*"},
+		{"\x00\x00\x00", "zv?F+"},
+		{"\xff\xff\xff", "{}ZO+"},
+		{"\x00\x00\x00\x00\x00", "(((((z?^4)"},
+	}
+	for _, tt := range tests {
+		got := PaddedEncoding.EncodeToString([]byte(tt.in))
+		if got != tt.want {
+			t.Errorf("Encode(%x) = %q, want %q", tt.in, got, tt.want)
+		}
+		dec, err := PaddedEncoding.DecodeString(tt.want)
+		if err != nil || string(dec) != tt.in {
+			t.Errorf("Decode(%q) = %x, %v, want %x", tt.want, dec, err, tt.in)
+		}
+	}
+}
+
+// TestPaddedRoundtrip verifies that PaddedEncoding output is a multiple
+// of 5 characters, matches r85 for whole blocks, and round-trips.
+func TestPaddedRoundtrip(t *testing.T) {
+	for n := range 300 {
+		src := makeSrc(n)
+		enc := PaddedEncoding.EncodeToString(src)
+		if len(enc)%5 != 0 || len(enc) != PaddedEncoding.MaxEncodedLen(n) {
+			t.Fatalf("n=%d: encoded length %d", n, len(enc))
+		}
+		if whole := n &^ 3; enc[:5*whole/4] != EncodeToString(src[:whole]) {
+			t.Fatalf("n=%d: full blocks differ from r85", n)
+		}
+		dec, err := PaddedEncoding.DecodeString(enc)
+		if err != nil || !bytes.Equal(dec, src) {
+			t.Fatalf("n=%d: Decode = %x, %v", n, dec, err)
+		}
+		got, err := io.ReadAll(PaddedEncoding.NewDecoder(iotest.HalfReader(strings.NewReader(enc))))
+		if err != nil || !bytes.Equal(got, src) {
+			t.Fatalf("n=%d: streaming Decode = %x, %v", n, got, err)
+		}
+	}
+}
+
+// TestPaddedErrors verifies that malformed padded text is rejected, and
+// that other encodings reject padded blocks.
+func TestPaddedErrors(t *testing.T) {
+	for _, s := range []string{
+		"z?^4)(((((", // data after the padded block
+		"z?^4)z?^4)",
+		"z?^7*",   // value too large for one byte
+		"{}ZO,",   // length field of 4
+		"((",      // short final block
+		"(((((((", // short final block after a full block
+	} {
+		if _, err := PaddedEncoding.DecodeString(s); err == nil {
+			t.Errorf("Decode(%q): expected error", s)
+		}
+		if _, err := io.ReadAll(PaddedEncoding.NewDecoder(iotest.OneByteReader(strings.NewReader(s)))); err == nil {
+			t.Errorf("streaming Decode(%q): expected error", s)
+		}
+	}
+	if _, err := DecodeString("z?^4)"); err == nil {
+		t.Error("StdEncoding accepted a padded block")
+	}
+	if got, err := PaddedEncoding.DecodeString("z?^4)\n "); err != nil || string(got) != "\x00" {
+		t.Errorf("Decode with trailing whitespace = %x, %v", got, err)
+	}
+}
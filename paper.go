@@ -0,0 +1,364 @@
+package r85
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// A paper backup is a sequence of text lines, each the r85 encoding of
+//
+//	uint32 data length, uint16 line index, uint16 line count,
+//	LineBytes bytes of payload, uint32 CRC-32 of the preceding bytes
+//
+// in big-endian order.  The payloads of the data lines hold the data,
+// padded with zeros.  The data lines are split into stripes of at most
+// 255-Parity lines, and each stripe is followed by Parity parity lines.
+// Each byte column of a stripe, data and parity lines together, is a
+// Reed-Solomon codeword over GF(2^8): the values at the points 0, 1, 2,
+// ... of the polynomial of least degree through the data bytes.  So any
+// Parity lines of a stripe can be lost or damaged, and the CRC-32 tells
+// which lines are damaged.
+
+// Errors returned by [ReadPaper].
+var (
+	ErrPaperNotFound      = errors.New("r85: no paper backup lines found")
+	ErrPaperUnrecoverable = errors.New("r85: too many damaged paper backup lines")
+	ErrPaperTooLarge      = errors.New("r85: data too large for a paper backup")
+)
+
+const (
+	paperHeaderLen   = 8
+	paperOverhead    = paperHeaderLen + 4
+	paperLineBytes   = 32
+	paperParity      = 4
+	paperMaxCodeword = 255
+)
+
+// PaperOptions configures [WritePaper].
+type PaperOptions struct {
+	// LineBytes is the number of data bytes per line, a positive
+	// multiple of 4.  Zero means 32, which gives 55-character lines.
+	LineBytes int
+	// Parity is the number of parity lines per stripe of up to
+	// 255-Parity data lines, from 1 to 254.  Zero means 4.
+	Parity int
+}
+
+// A PaperCorrection is a repair made by [ReadPaper].
+type PaperCorrection struct {
+	// Row is the index of the repaired line, counting from 0 and
+	// including parity lines.
+	Row int
+	// Column is the offset within the line's text, not counting
+	// whitespace, of the 5-character block that was repaired, or -1 if
+	// the line was missing or unreadable, or only its index or checksum
+	// were damaged.  A line with several repaired blocks has a
+	// correction for each.
+	Column int
+}
+
+// WritePaper writes data to w as a paper backup: lines of r85 text that
+// [ReadPaper] can decode even if some lines are lost or damaged.
+// Within each stripe of data lines, any opts.Parity lines can be
+// recovered.  WritePaper panics if opts is invalid, and reports
+// [ErrPaperTooLarge] if the backup would need more than 65535 lines.
+func WritePaper(w io.Writer, data []byte, opts PaperOptions) error {
+	lineBytes, parity := opts.LineBytes, opts.Parity
+	if lineBytes == 0 {
+		lineBytes = paperLineBytes
+	}
+	if parity == 0 {
+		parity = paperParity
+	}
+	if lineBytes < 0 || lineBytes%4 != 0 || parity < 0 || parity >= paperMaxCodeword {
+		panic("r85: invalid paper backup options")
+	}
+	rows := max(1, (len(data)+lineBytes-1)/lineBytes)
+	total := paperLines(rows, parity)
+	if uint64(len(data)) > 0xFFFFFFFF || total > 0xFFFF {
+		return ErrPaperTooLarge
+	}
+
+	bw := bufio.NewWriter(w)
+	line := make([]byte, paperOverhead+lineBytes)
+	text := make([]byte, MaxEncodedLen(len(line))+1)
+	index := 0
+	emit := func(payload []byte) {
+		binary.BigEndian.PutUint32(line[0:], uint32(len(data)))
+		binary.BigEndian.PutUint16(line[4:], uint16(index))
+		binary.BigEndian.PutUint16(line[6:], uint16(total))
+		n := copy(line[paperHeaderLen:], payload)
+		clear(line[paperHeaderLen+n : paperHeaderLen+lineBytes])
+		body := line[:paperHeaderLen+lineBytes]
+		binary.BigEndian.PutUint32(line[len(body):], crc32.ChecksumIEEE(body))
+		n = Encode(text, line)
+		text[n] = '\n'
+		bw.Write(text[:n+1])
+		index++
+	}
+
+	stripe := paperMaxCodeword - parity
+	for first := 0; first < rows; first += stripe {
+		k := min(stripe, rows-first)
+		shards := make([][]byte, k+parity)
+		for i := range k {
+			lo := min(len(data), (first+i)*lineBytes)
+			shards[i] = make([]byte, lineBytes)
+			copy(shards[i], data[lo:min(len(data), lo+lineBytes)])
+			emit(shards[i])
+		}
+		known := make([]int, k)
+		for i := range known {
+			known[i] = i
+		}
+		for i := k; i < k+parity; i++ {
+			shards[i] = make([]byte, lineBytes)
+		}
+		rsInterpolate(shards, known, shards[k:], k)
+		for _, p := range shards[k:] {
+			emit(p)
+		}
+	}
+	return bw.Flush()
+}
+
+// paperLines returns the number of lines of a paper backup with the
+// given numbers of data lines and parity lines per stripe.
+func paperLines(rows, parity int) int {
+	stripe := paperMaxCodeword - parity
+	return rows + parity*((rows+stripe-1)/stripe)
+}
+
+// paperLine is a line of a paper backup as read by ReadPaper.
+type paperLine struct {
+	raw   []byte // decoded bytes, or nil if the text did not decode
+	good  bool   // whether the checksum matched
+	index int
+}
+
+// ReadPaper reads a paper backup written by [WritePaper] from r, repairs
+// lost and damaged lines, and returns the data and the repairs made.
+// Blank lines are ignored, and so is whitespace within lines.  A damaged
+// line is placed by the lines around it, so lines must be in order.
+// ReadPaper reports [ErrPaperUnrecoverable] if any stripe has more
+// damaged lines than parity lines.
+func ReadPaper(r io.Reader) ([]byte, []PaperCorrection, error) {
+	var lines []paperLine
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		text := bytes.TrimSpace(sc.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		l := paperLine{index: -1}
+		if raw, err := DecodeString(string(text)); err == nil {
+			l.raw = raw
+			if n := len(raw) - 4; n >= paperHeaderLen && crc32.ChecksumIEEE(raw[:n]) == binary.BigEndian.Uint32(raw[n:]) {
+				l.good = true
+				l.index = int(binary.BigEndian.Uint16(raw[4:]))
+			}
+		}
+		lines = append(lines, l)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// The first good line sets the layout; other lines must agree.
+	var ref []byte
+	for _, l := range lines {
+		if l.good {
+			ref = l.raw
+			break
+		}
+	}
+	if ref == nil {
+		return nil, nil, ErrPaperNotFound
+	}
+	size := int(binary.BigEndian.Uint32(ref))
+	total := int(binary.BigEndian.Uint16(ref[6:]))
+	lineBytes := len(ref) - paperOverhead
+	for i, l := range lines {
+		if l.good && (len(l.raw) != len(ref) || !bytes.Equal(l.raw[:4], ref[:4]) ||
+			!bytes.Equal(l.raw[6:8], ref[6:8]) || l.index >= total) {
+			lines[i].good, lines[i].index = false, -1
+		}
+	}
+	rows := 1
+	if lineBytes > 0 {
+		rows = max(1, (size+lineBytes-1)/lineBytes)
+	}
+	parity := -1
+	for p := range paperMaxCodeword {
+		if paperLines(rows, p) == total {
+			parity = p
+			break
+		}
+	}
+	if lineBytes <= 0 || parity < 0 {
+		return nil, nil, ErrPaperNotFound
+	}
+	// Each stripe needs as many lines as it has data lines, so check
+	// that the lines read could hold the data before allocating for the
+	// size that the header claims.
+	if len(lines) < rows {
+		return nil, nil, ErrPaperUnrecoverable
+	}
+
+	// Place damaged lines between the good lines around them, if the
+	// number of lines between them matches.
+	shards := make([][]byte, total)
+	damaged := make([][]byte, total)
+	prev, pending := -1, []int(nil)
+	place := func(next int) {
+		if len(pending) == next-prev-1 {
+			for j, i := range pending {
+				if len(lines[i].raw) == len(ref) {
+					damaged[prev+1+j] = lines[i].raw[paperHeaderLen : paperHeaderLen+lineBytes]
+				}
+			}
+		}
+		pending = pending[:0]
+	}
+	for i, l := range lines {
+		if !l.good {
+			pending = append(pending, i)
+			continue
+		}
+		if l.index <= prev || shards[l.index] != nil {
+			continue
+		}
+		place(l.index)
+		shards[l.index] = l.raw[paperHeaderLen : paperHeaderLen+lineBytes]
+		prev = l.index
+	}
+	place(total)
+
+	var fixes []PaperCorrection
+	stripe := paperMaxCodeword - parity
+	data := make([]byte, 0, rows*lineBytes)
+	for first, row := 0, 0; first < rows; first += stripe {
+		k := min(stripe, rows-first)
+		s := shards[row : row+k+parity]
+		var known, lost []int
+		for i, b := range s {
+			if b != nil {
+				known = append(known, i)
+			} else {
+				lost = append(lost, i)
+			}
+		}
+		if len(known) < k {
+			return nil, fixes, ErrPaperUnrecoverable
+		}
+		if len(lost) > 0 {
+			out := make([][]byte, len(lost))
+			for i := range out {
+				out[i] = make([]byte, lineBytes)
+			}
+			rsRecover(s, known[:k], lost, out)
+			for i, j := range lost {
+				s[j] = out[i]
+				fixes = append(fixes, paperFixes(row+j, out[i], damaged[row+j])...)
+			}
+		}
+		for _, b := range s[:k] {
+			data = append(data, b...)
+		}
+		row += k + parity
+	}
+	return data[:size], fixes, nil
+}
+
+// paperFixes lists the corrections made to one line, given its repaired
+// payload and its damaged payload, if it was readable.
+func paperFixes(row int, fixed, damaged []byte) []PaperCorrection {
+	var fixes []PaperCorrection
+	if damaged != nil {
+		for i := range fixed {
+			col := 5 * ((paperHeaderLen + i) / 4)
+			if fixed[i] != damaged[i] && (len(fixes) == 0 || fixes[len(fixes)-1].Column != col) {
+				fixes = append(fixes, PaperCorrection{row, col})
+			}
+		}
+	}
+	if len(fixes) == 0 {
+		fixes = append(fixes, PaperCorrection{row, -1})
+	}
+	return fixes
+}
+
+// gfExp and gfLog are exponent and logarithm tables for GF(2^8) with the
+// polynomial x^8 + x^4 + x^3 + x^2 + 1 and generator 2.
+var gfExp [510]byte
+var gfLog [256]int
+
+func init() {
+	x := 1
+	for i := range 255 {
+		gfExp[i], gfExp[i+255] = byte(x), byte(x)
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+255-gfLog[b]]
+}
+
+// rsInterpolate sets out[i], for each i, to the values at the point
+// first+i of the polynomials through the shards at the points known.
+// Each byte column is a separate polynomial.
+func rsInterpolate(shards [][]byte, known []int, out [][]byte, first int) {
+	points := make([]int, len(out))
+	for i := range points {
+		points[i] = first + i
+	}
+	rsRecover(shards, known, points, out)
+}
+
+// rsRecover sets out[i] to the values at the point points[i] of the
+// polynomials through the shards at the points known, by Lagrange
+// interpolation.  Points are field elements, so there are at most 256.
+func rsRecover(shards [][]byte, known, points []int, out [][]byte) {
+	coef := make([]byte, len(known))
+	for i, x := range points {
+		// coef[j] is the Lagrange basis polynomial for known[j] at x.
+		for j, xj := range known {
+			c := byte(1)
+			for _, xm := range known {
+				if xm != xj {
+					c = gfMul(c, gfDiv(byte(x^xm), byte(xj^xm)))
+				}
+			}
+			coef[j] = c
+		}
+		clear(out[i])
+		for j, xj := range known {
+			c := coef[j]
+			if c == 0 {
+				continue
+			}
+			for col, v := range shards[xj] {
+				out[i][col] ^= gfMul(c, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package r85
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writePaper returns the lines of the paper backup of data.
+func writePaper(t *testing.T, data []byte, opts PaperOptions) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WritePaper(&buf, data, opts); err != nil {
+		t.Fatal(err)
+	}
+	return strings.SplitAfter(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+// readPaper reads a paper backup from lines.
+func readPaper(lines []string) ([]byte, []PaperCorrection, error) {
+	return ReadPaper(strings.NewReader(strings.Join(lines, "")))
+}
+
+// TestPaperRoundtrip verifies that undamaged backups of many sizes
+// decode without corrections.
+func TestPaperRoundtrip(t *testing.T) {
+	for _, opts := range []PaperOptions{{}, {LineBytes: 4, Parity: 1}, {LineBytes: 8, Parity: 200}} {
+		for _, n := range []int{0, 1, 31, 32, 33, 500, 3000} {
+			data := makeSrc(n)
+			lines := writePaper(t, data, opts)
+			got, fixes, err := readPaper(lines)
+			if err != nil || !bytes.Equal(got, data) || len(fixes) != 0 {
+				t.Fatalf("%+v, n=%d: ReadPaper = %d bytes, %v, %v", opts, n, len(got), fixes, err)
+			}
+		}
+	}
+}
+
+// TestPaperLineFormat checks the line length and the line count.
+func TestPaperLineFormat(t *testing.T) {
+	lines := writePaper(t, makeSrc(100), PaperOptions{})
+	// 4 data lines and one stripe of 4 parity lines.
+	if len(lines) != 8 {
+		t.Errorf("got %d lines, want 8", len(lines))
+	}
+	for _, l := range lines {
+		if l = strings.TrimSuffix(l, "\n"); len(l) != 55 {
+			t.Errorf("line %q has length %d, want 55", l, len(l))
+		}
+	}
+}
+
+// TestPaperRepairs damages and removes lines and checks the reported
+// corrections.
+func TestPaperRepairs(t *testing.T) {
+	data := makeSrc(300)
+	lines := writePaper(t, data, PaperOptions{})
+
+	// Change the first character of line 1, which changes the length
+	// field only; change a character of line 3 in the block that holds
+	// payload bytes 8 to 11; drop line 5; and make line 6 unreadable.
+	damaged := slices.Clone(lines)
+	damaged[1] = "|" + damaged[1][1:]
+	b := []byte(damaged[3])
+	b[24] = '('
+	if b[24] == lines[3][24] {
+		b[24] = ')'
+	}
+	damaged[3] = string(b)
+	damaged[6] = "!!!\n"
+	damaged = slices.Delete(damaged, 5, 6)
+
+	got, fixes, err := readPaper(damaged)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("ReadPaper = %v", err)
+	}
+	want := []PaperCorrection{{1, -1}, {3, 20}, {5, -1}, {6, -1}}
+	if !slices.Equal(fixes, want) {
+		t.Errorf("corrections = %v, want %v", fixes, want)
+	}
+}
+
+// TestPaperStripes verifies that each stripe can lose Parity lines.
+func TestPaperStripes(t *testing.T) {
+	data := makeSrc(8 * 120)
+	opts := PaperOptions{LineBytes: 8, Parity: 200}
+	lines := writePaper(t, data, opts)
+	// 120 data lines in 3 stripes of up to 55, each with 200 parity lines.
+	if len(lines) != 720 {
+		t.Fatalf("got %d lines, want 720", len(lines))
+	}
+	damaged := slices.Clone(lines)
+	for i := range damaged {
+		if i%3 != 0 {
+			damaged[i] = "?\n"
+		}
+	}
+	got, _, err := readPaper(damaged)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("ReadPaper with 2/3 of lines lost: %v", err)
+	}
+}
+
+// TestPaperErrors checks unrecoverable and missing backups.
+func TestPaperErrors(t *testing.T) {
+	lines := writePaper(t, makeSrc(64), PaperOptions{Parity: 1})
+	if _, _, err := readPaper(lines[1:2]); err != ErrPaperUnrecoverable {
+		t.Errorf("two lost lines: err = %v, want ErrPaperUnrecoverable", err)
+	}
+	if _, _, err := readPaper([]string{"hello\n", "\n"}); err != ErrPaperNotFound {
+		t.Errorf("no backup: err = %v, want ErrPaperNotFound", err)
+	}
+}
+
+// TestPaperForgedSize checks that a line whose header claims far more
+// data than the lines present is rejected without allocating for it.
+func TestPaperForgedSize(t *testing.T) {
+	// 65000 lines of 4000 bytes, with one parity line per stripe.
+	line := make([]byte, paperOverhead+4000)
+	binary.BigEndian.PutUint32(line, 65000*4000)
+	binary.BigEndian.PutUint16(line[6:], uint16(paperLines(65000, 1)))
+	binary.BigEndian.PutUint32(line[len(line)-4:], crc32.ChecksumIEEE(line[:len(line)-4]))
+	text := EncodeToString(line) + "\n"
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, _, err := readPaper([]string{text})
+	runtime.ReadMemStats(&after)
+	if err != ErrPaperUnrecoverable {
+		t.Errorf("err = %v, want ErrPaperUnrecoverable", err)
+	}
+	if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+		t.Errorf("ReadPaper allocated %d bytes", n)
+	}
+}
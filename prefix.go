@@ -0,0 +1,107 @@
+package r85
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// DecodePrefix decodes the r85 text at the start of src, up to the first
+// byte that is not in the alphabet: see [Encoding.DecodePrefix].
+func DecodePrefix(dst, src []byte) (ndst, nsrc int, err error) {
+	return StdEncoding.DecodePrefix(dst, src)
+}
+
+// DecodeUntil decodes src up to the first byte in terminators: see
+// [Encoding.DecodeUntil].
+func DecodeUntil(dst, src []byte, terminators string) (ndst, nsrc int, err error) {
+	return StdEncoding.DecodeUntil(dst, src, terminators)
+}
+
+// NewPrefixDecoder returns a decoder for the r85 text at the start of r:
+// see [Encoding.NewPrefixDecoder].
+func NewPrefixDecoder(r *bufio.Reader, terminators string, opts ...DecoderOption) io.Reader {
+	return StdEncoding.NewPrefixDecoder(r, terminators, opts...)
+}
+
+// DecodePrefix decodes the text at the start of src, up to the first
+// byte that is neither a digit, an alias nor one of enc's shortcuts, so
+// a token can be taken out of a larger text such as "id=...&x=1".  nsrc
+// is the number of bytes consumed, which is the offset of that byte, or
+// len(src) if there is none.  The token ends the text, so its final
+// block may be partial.  Framing and grouping are not used.
+func (enc *Encoding) DecodePrefix(dst, src []byte) (ndst, nsrc int, err error) {
+	return enc.DecodeUntil(dst, src, "")
+}
+
+// DecodeUntil is like [Encoding.DecodePrefix], but stops at the first
+// byte in terminators instead; other bytes outside the alphabet are
+// skipped, as by [Encoding.Decode].  An empty terminators stops at any
+// byte outside the alphabet.
+func (enc *Encoding) DecodeUntil(dst, src []byte, terminators string) (ndst, nsrc int, err error) {
+	n := enc.tokenLen(src, terminators)
+	ndst, nsrc, err = enc.decodeBlocks(dst, src[:n])
+	if err != nil {
+		return ndst, nsrc, err
+	}
+	return ndst, n, nil
+}
+
+// tokenLen returns the length of the token at the start of src: the
+// offset of the first byte in terminators, or if terminators is empty,
+// of the first byte outside the alphabet.
+func (enc *Encoding) tokenLen(src []byte, terminators string) int {
+	for i, c := range src {
+		if terminators == "" {
+			if v := enc.decodeMap[c]; v == digitSkip || v == digitReserved {
+				return i
+			}
+		} else if strings.IndexByte(terminators, c) >= 0 {
+			return i
+		}
+	}
+	return len(src)
+}
+
+// NewPrefixDecoder returns a decoder for the text at the start of r, which
+// ends like a token of [Encoding.DecodeUntil].  The decoder reads through
+// r's buffer without going past the end of the token, so the byte that
+// ended it, and everything after, is left unread in r.  Framing is not
+// used.
+func (enc *Encoding) NewPrefixDecoder(r *bufio.Reader, terminators string, opts ...DecoderOption) io.Reader {
+	e := *enc
+	e.prefix, e.suffix = "", ""
+	return e.NewDecoder(&prefixReader{enc: enc, r: r, terminators: terminators}, opts...)
+}
+
+// prefixReader reads a token from the start of a bufio.Reader, and
+// reports EOF at its end without consuming the terminating byte.
+type prefixReader struct {
+	enc         *Encoding
+	r           *bufio.Reader
+	terminators string
+	done        bool
+}
+
+func (p *prefixReader) Read(b []byte) (int, error) {
+	if p.done {
+		return 0, io.EOF
+	}
+	if p.r.Buffered() == 0 {
+		if _, err := p.r.Peek(1); err != nil {
+			return 0, err
+		}
+	}
+	buf, _ := p.r.Peek(p.r.Buffered())
+	buf = buf[:min(len(buf), len(b))]
+	n := p.enc.tokenLen(buf, p.terminators)
+	if n < len(buf) {
+		p.done = true
+	}
+	copy(b, buf[:n])
+	p.r.Discard(n)
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
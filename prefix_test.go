@@ -0,0 +1,82 @@
+package r85
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestDecodePrefix checks that DecodePrefix stops at the first byte
+// that is not an r85 character, whatever follows the token.
+func TestDecodePrefix(t *testing.T) {
+	for _, n := range []int{0, 1, 4, 7, 100} {
+		src := makeSrc(n)
+		tok := EncodeToString(src)
+		for _, rest := range []string{"", "&x=1", " tail", "!", "\x80"} {
+			dst := make([]byte, n)
+			ndst, nsrc, err := DecodePrefix(dst, []byte(tok+rest))
+			if err != nil || nsrc != len(tok) || !bytes.Equal(dst[:ndst], src) {
+				t.Errorf("DecodePrefix(%d bytes + %q) = %d, %d, %v; want %d, %d, nil",
+					n, rest, ndst, nsrc, err, n, len(tok))
+			}
+		}
+	}
+}
+
+// TestDecodePrefixCorrupt checks that an overflowing block in the token
+// is reported at its offset.
+func TestDecodePrefixCorrupt(t *testing.T) {
+	dst := make([]byte, 16)
+	_, _, err := DecodePrefix(dst, []byte("(((((|||||&x"))
+	if ce, ok := err.(CorruptInputError); !ok || ce.Offset != 5 {
+		t.Errorf("err = %v, want CorruptInputError at offset 5", err)
+	}
+}
+
+// TestDecodeUntil checks that DecodeUntil skips whitespace within the
+// token and consumes the terminator.
+func TestDecodeUntil(t *testing.T) {
+	src := makeSrc(40)
+	tok := EncodeToString(src)
+	text := tok[:20] + " " + tok[20:] + "\nnext line"
+	dst := make([]byte, len(src))
+	ndst, nsrc, err := DecodeUntil(dst, []byte(text), "\n")
+	if err != nil || nsrc != len(tok)+1 || !bytes.Equal(dst[:ndst], src) {
+		t.Errorf("DecodeUntil = %d, %d, %v; want %d, %d, nil", ndst, nsrc, err, len(src), len(tok)+1)
+	}
+}
+
+// TestPrefixDecoder checks that the streaming decoder leaves the text
+// after the token unread, with small buffers and one-byte reads.
+func TestPrefixDecoder(t *testing.T) {
+	src := makeSrc(1000)
+	tok := EncodeToString(src)
+	for _, tt := range []struct {
+		name        string
+		terminators string
+		text        string
+		rest        string
+	}{
+		{"alphabet", "", tok + "&x=1", "&x=1"},
+		{"at EOF", "", tok, ""},
+		{"terminator", "\n", tok[:100] + "\r\t" + tok[100:] + "\nrest", "\nrest"},
+	} {
+		for _, one := range []bool{false, true} {
+			var r io.Reader = strings.NewReader(tt.text)
+			if one {
+				r = iotest.OneByteReader(r)
+			}
+			br := bufio.NewReaderSize(r, 16)
+			got, err := io.ReadAll(NewPrefixDecoder(br, tt.terminators))
+			if err != nil || !bytes.Equal(got, src) {
+				t.Errorf("%s: got %d bytes, %v; want %d bytes", tt.name, len(got), err, len(src))
+			}
+			if rest, _ := io.ReadAll(br); string(rest) != tt.rest {
+				t.Errorf("%s: left %q unread, want %q", tt.name, rest, tt.rest)
+			}
+		}
+	}
+}
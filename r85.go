@@ -1,6 +1,10 @@
 package r85
 
-import "io"
+import (
+	"bytes"
+	"errors"
+	"io"
+)
 
 // MaxEncodedLen returns the maximum length of an encoding of n source bytes.
 func MaxEncodedLen(n int) int {
@@ -38,13 +42,15 @@ var encTable = [85]byte{
 }
 
 // decTable maps an encoded byte to its r85 digit value (0–84),
+// 0xFE if the byte is reserved for the r85z shortcuts and the segment
+// delimiter ('!' and '#'),
 // or 0xFF if the byte is not in the r85 alphabet.
 // Both the canonical encoded forms ('}' for 20, '~' for 56) and their
 // unescaped equivalents ('<' for 20, '`' for 56) are accepted.
 var decTable = [256]byte{
 	0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, // 0–15
 	0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, // 16–31
-	0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, // 32–47: '(' is 40=0x00
+	0xFF, 0xFE, 0xFF, 0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, // 32–47: '!' and '#' reserved, '(' is 40=0x00
 	0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, // 48–63: '<' (60)=0x14=20
 	0x18, 0x19, 0x1A, 0x1B, 0x1C, 0x1D, 0x1E, 0x1F, 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, // 64–79
 	0x28, 0x29, 0x2A, 0x2B, 0x2C, 0x2D, 0x2E, 0x2F, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, // 80–95
@@ -68,7 +74,7 @@ func encByte(v byte) byte {
 // Returns the digit value and 1 if valid, or (0, 0) if invalid.
 func decByte(b byte) (byte, byte) {
 	v := decTable[b]
-	if v == 0xFF {
+	if v >= 85 {
 		return 0, 0
 	}
 	return v, 1
@@ -79,95 +85,287 @@ func decByte(b byte) (byte, byte) {
 // If dst is too short, Encode fills dst and returns len(dst),
 // and ignores the remaining input.
 func Encode(dst, src []byte) int {
+	return StdEncoding.Encode(dst, src)
+}
+
+// EncodeToString returns the r85 encoding of src as a string.
+func EncodeToString(src []byte) string {
+	return StdEncoding.EncodeToString(src)
+}
+
+// DecodeString returns the bytes represented by the r85 string s.
+func DecodeString(s string) ([]byte, error) {
+	return StdEncoding.DecodeString(s)
+}
+
+// Decode decodes text src into binary dst.
+// ndst contains the number of bytes written into dst.
+// nsrc contains the number of bytes consumed from src, including
+// skipped bytes.
+// If dst is too short, Decode fills dst and returns len(dst),
+// and ignores the remaining input.
+func Decode(dst, src []byte) (ndst, nsrc int, err error) {
+	return StdEncoding.Decode(dst, src)
+}
+
+// Encode encodes binary src into text dst using enc, returning the
+// number of bytes written to dst.
+// If dst is too short, Encode fills dst and returns len(dst),
+// and ignores the remaining input.
+// If enc uses [PartialNone], len(src) must be a multiple of 4, as
+// [Encoding.CheckEncodeLen] reports; otherwise Encode panics.
+func (enc *Encoding) Encode(dst, src []byte) int {
+	if err := enc.CheckEncodeLen(len(src)); err != nil {
+		panic(err)
+	}
+	di := copy(dst, enc.prefix)
+	if di < len(enc.prefix) {
+		return di
+	}
+	di += enc.group(dst[di:], enc.encodeBlocks(dst[di:], src))
+	return di + copy(dst[di:], enc.suffix)
+}
+
+// EncodeToString returns the encoding of src using enc as a string.
+func (enc *Encoding) EncodeToString(src []byte) string {
+	dst := make([]byte, enc.MaxEncodedLen(len(src)))
+	n := enc.Encode(dst, src)
+	return string(dst[:n])
+}
+
+// encodeBlocks encodes src into dst without framing.
+func (enc *Encoding) encodeBlocks(dst, src []byte) int {
 	di := 0
 	si := 0
 
 	// SIMD fast path: process 64-byte blocks.
 	if haveSIMD {
 		for si+64 <= len(src) && di+80 <= len(dst) {
-			encodeBlocksSIMD(&dst[di], &src[si])
+			if enc.hasShortcut(src[si : si+64]) {
+				// Extend the chunk to the end of any zero run, so that
+				// runs are not split at chunk boundaries.
+				end := si + 64
+				if enc.zeroRun != 0 {
+					end += 4 * zeroWords(src[end:], len(src))
+				}
+				n, m := enc.encodeWords(dst[di:], src[si:end])
+				di += n
+				si += m
+				if si < end {
+					return len(dst)
+				}
+				continue
+			}
+			if enc.remap {
+				encodeBlocksRowsSIMD(&dst[di], &src[si], &enc.encRows)
+			} else {
+				encodeBlocksSIMD(&dst[di], &src[si])
+			}
 			di += 80
 			si += 64
 		}
 	}
 
 	// Process full 4-byte blocks.
+	n, m := enc.encodeWords(dst[di:], src[si:])
+	di += n
+	si += m
+	if si+4 <= len(src) {
+		return len(dst)
+	}
+
+	// Handle trailing 1–3 bytes.
+	r := len(src) - si
+	if r == 0 {
+		return di
+	}
+	w := r + 1
+	if enc.partial == PartialZeroPad || enc.partial == PartialPadded {
+		w = 5
+	}
+	if di+w > len(dst) {
+		return len(dst)
+	}
+	var acc uint32
+	for _, b := range src[si:] {
+		acc = acc<<8 | uint32(b)
+	}
+	switch enc.partial {
+	case PartialValue:
+		enc.putDigits(dst[di:di+w], acc)
+	case PartialNone:
+		panic(ErrPartialBlock)
+	case PartialTruncate, PartialZeroPad:
+		var block [5]byte
+		enc.putDigits(block[:], acc<<(8*(4-r)))
+		copy(dst[di:di+w], block[:])
+	case PartialPadded:
+		// The value does not fit in 32 bits, so putDigits cannot be used.
+		v := paddedBase + uint64(r-1)<<24 + uint64(acc)
+		for i := di + 4; i >= di; i-- {
+			dst[i] = enc.encode[v%85]
+			v /= 85
+		}
+	}
+	return di + w
+}
+
+// encodeWords encodes the full 4-byte blocks of src into dst, stopping
+// early if dst is too short.  It returns the number of bytes written to
+// dst and consumed from src.
+func (enc *Encoding) encodeWords(dst, src []byte) (ndst, nsrc int) {
+	di := 0
+	si := 0
 	for si+4 <= len(src) {
+		acc := uint32(src[si])<<24 | uint32(src[si+1])<<16 | uint32(src[si+2])<<8 | uint32(src[si+3])
+		if acc == 0 && enc.zeroRun != 0 {
+			if run := zeroWords(src[si:], maxZeroRun); run >= minZeroRun {
+				if di+2 > len(dst) {
+					break
+				}
+				dst[di] = enc.zeroRun
+				dst[di+1] = enc.encode[run-minZeroRun]
+				di += 2
+				si += 4 * run
+				continue
+			}
+		}
+		if (acc == 0 && enc.zero != 0) || (acc == 0x20202020 && enc.spaces != 0) {
+			if di+1 > len(dst) {
+				break
+			}
+			if acc == 0 {
+				dst[di] = enc.zero
+			} else {
+				dst[di] = enc.spaces
+			}
+			di++
+			si += 4
+			continue
+		}
 		if di+5 > len(dst) {
-			return len(dst)
+			break
 		}
-		acc := uint32(src[si])<<24 | uint32(src[si+1])<<16 | uint32(src[si+2])<<8 | uint32(src[si+3])
-		dst[di+4] = encByte(byte(acc % 85))
+		dst[di+4] = enc.encode[acc%85]
 		acc /= 85
-		dst[di+3] = encByte(byte(acc % 85))
+		dst[di+3] = enc.encode[acc%85]
 		acc /= 85
-		dst[di+2] = encByte(byte(acc % 85))
+		dst[di+2] = enc.encode[acc%85]
 		acc /= 85
-		dst[di+1] = encByte(byte(acc % 85))
+		dst[di+1] = enc.encode[acc%85]
 		acc /= 85
-		dst[di+0] = encByte(byte(acc))
+		dst[di+0] = enc.encode[acc]
 		di += 5
 		si += 4
 	}
+	return di, si
+}
 
-	// Handle trailing 1–3 bytes.
-	switch len(src) - si {
-	case 1:
-		if di+2 > len(dst) {
-			return len(dst)
-		}
-		acc := uint32(src[si])
-		dst[di+1] = encByte(byte(acc % 85))
-		dst[di+0] = encByte(byte(acc / 85))
-		di += 2
-	case 2:
-		if di+3 > len(dst) {
-			return len(dst)
-		}
-		acc := uint32(src[si])<<8 | uint32(src[si+1])
-		dst[di+2] = encByte(byte(acc % 85))
-		acc /= 85
-		dst[di+1] = encByte(byte(acc % 85))
-		dst[di+0] = encByte(byte(acc / 85))
-		di += 3
-	case 3:
-		if di+4 > len(dst) {
-			return len(dst)
+// putDigits writes the len(dst) least significant base-85 digits of v
+// to dst, most significant first.
+func (enc *Encoding) putDigits(dst []byte, v uint32) {
+	for i := len(dst) - 1; i >= 0; i-- {
+		dst[i] = enc.encode[v%85]
+		v /= 85
+	}
+}
+
+// zeroWords returns the number of consecutive all-zero 4-byte blocks at
+// the start of b, up to max.
+func zeroWords(b []byte, max int) int {
+	n := 0
+	for n < max && 4*n+4 <= len(b) && b[4*n]|b[4*n+1]|b[4*n+2]|b[4*n+3] == 0 {
+		n++
+	}
+	return n
+}
+
+// zeroBlocks is a zero run of the greatest length that one shortcut
+// encodes.
+var zeroBlocks [4 * maxZeroRun]byte
+
+// encodeStream encodes the full 4-byte blocks of src into dst for a
+// streaming encoder, stopping early if dst is too short.  If enc has a
+// zero-run shortcut, all-zero blocks are not encoded but counted in
+// *zeros, and encoded by endZeros before the next other block, so that a
+// run split between writes is encoded as Encode would encode it.  It
+// returns the number of bytes written to dst and consumed from src.
+func (enc *Encoding) encodeStream(dst, src []byte, zeros *int) (ndst, nsrc int) {
+	if enc.zeroRun == 0 {
+		k := min(len(src)&^3, len(dst)/5*4)
+		return enc.encodeBlocks(dst, src[:k]), k
+	}
+	di, si := 0, 0
+	for si+4 <= len(src) {
+		if z := zeroWords(src[si:], len(src)); z > 0 {
+			*zeros += z
+			si += 4 * z
+			continue
+		}
+		end := si + 4
+		for end+4 <= len(src) && src[end]|src[end+1]|src[end+2]|src[end+3] != 0 {
+			end += 4
+		}
+		di += enc.endZeros(dst[di:], zeros)
+		k := min(end-si, (len(dst)-di)/5*4)
+		if *zeros > 0 || k == 0 {
+			break
+		}
+		di += enc.encodeBlocks(dst[di:], src[si:si+k])
+		si += k
+		if si < end {
+			break
 		}
-		acc := uint32(src[si])<<16 | uint32(src[si+1])<<8 | uint32(src[si+2])
-		dst[di+3] = encByte(byte(acc % 85))
-		acc /= 85
-		dst[di+2] = encByte(byte(acc % 85))
-		acc /= 85
-		dst[di+1] = encByte(byte(acc % 85))
-		dst[di+0] = encByte(byte(acc / 85))
-		di += 4
 	}
+	return di, si
+}
 
+// endZeros encodes as many of the *zeros all-zero blocks held back by
+// encodeStream as fit in dst, and returns the number of bytes written.
+func (enc *Encoding) endZeros(dst []byte, zeros *int) int {
+	di := 0
+	for *zeros > 0 {
+		k, need := min(*zeros, maxZeroRun), 2
+		if k < minZeroRun {
+			need = 5 * k
+		}
+		if di+need > len(dst) {
+			break
+		}
+		n, _ := enc.encodeWords(dst[di:], zeroBlocks[:4*k])
+		di += n
+		*zeros -= k
+	}
 	return di
 }
 
-// EncodeToString returns the r85 encoding of src as a string.
-func EncodeToString(src []byte) string {
-	dst := make([]byte, MaxEncodedLen(len(src)))
-	n := Encode(dst, src)
-	return string(dst[:n])
+// hasShortcut reports whether any 4-byte block of b would be encoded
+// using one of enc's shortcut characters.
+func (enc *Encoding) hasShortcut(b []byte) bool {
+	zero := enc.zero != 0 || enc.zeroRun != 0
+	if !zero && enc.spaces == 0 {
+		return false
+	}
+	for i := 0; i+4 <= len(b); i += 4 {
+		w := uint32(b[i])<<24 | uint32(b[i+1])<<16 | uint32(b[i+2])<<8 | uint32(b[i+3])
+		if (w == 0 && zero) || (w == 0x20202020 && enc.spaces != 0) {
+			return true
+		}
+	}
+	return false
 }
 
-// DecodeString returns the bytes represented by the r85 string s.
-func DecodeString(s string) ([]byte, error) {
-	src := []byte(s)
-	dst := make([]byte, MaxDecodedLen(len(src)))
-	ndst, _, err := Decode(dst, src)
-	return dst[:ndst], err
+// remapBlock maps the 80 characters of in to r85 characters in tmp, and
+// reports whether they are all digits.
+func (enc *Encoding) remapBlock(tmp *[80]byte, in []byte) bool {
+	low := byte(0xFF)
+	for i, c := range (*[80]byte)(in) {
+		tmp[i] = enc.toR85[c]
+		low = min(low, tmp[i])
+	}
+	return low != 0
 }
 
-// Decode decodes text src into binary dst.
-// ndst contains the number of bytes written into dst.
-// nsrc contains the number of bytes consumed from src, including
-// skipped bytes.
-// If dst is too short, Decode fills dst and returns len(dst),
-// and ignores the remaining input.
 // allValidR85 reports whether all bytes in b are valid r85 characters
 // (in the range [40, 126]).
 func allValidR85(b []byte) bool {
@@ -179,17 +377,80 @@ func allValidR85(b []byte) bool {
 	return true
 }
 
-func Decode(dst, src []byte) (ndst, nsrc int, err error) {
+// DecodeString returns the bytes represented by the string s in enc.
+func (enc *Encoding) DecodeString(s string) ([]byte, error) {
+	src := []byte(s)
+	dst := make([]byte, enc.decodedLen(src))
+	ndst, _, err := enc.Decode(dst, src)
+	return dst[:ndst], err
+}
+
+// Decode decodes text src into binary dst using enc.
+// ndst contains the number of bytes written into dst.
+// nsrc contains the number of bytes consumed from src, including
+// skipped bytes and framing.
+// If dst is too short, Decode fills dst and returns len(dst),
+// and ignores the remaining input.
+//
+// If enc has framing, an optional prefix is skipped after any leading
+// whitespace, and decoding stops after the suffix, which is required.
+func (enc *Encoding) Decode(dst, src []byte) (ndst, nsrc int, err error) {
+	if enc.suffix == "" {
+		return enc.decodeBlocks(dst, src)
+	}
+	start, end := enc.frame(src)
+	if end < 0 {
+		ndst, nsrc, err = enc.decodeBlocks(dst, src[start:])
+		if err == nil {
+			return ndst, start + nsrc, CorruptInputError{Reason: "missing end delimiter"}
+		}
+		return ndst, start + nsrc, shiftOffset(err, start)
+	}
+	ndst, nsrc, err = enc.decodeBlocks(dst, src[start:end])
+	if err != nil || start+nsrc < end {
+		return ndst, start + nsrc, shiftOffset(err, start)
+	}
+	return ndst, end + len(enc.suffix), nil
+}
+
+// frame returns the bounds of the text between enc's framing in src.
+// The prefix is optional; end is -1 if there is no suffix.
+func (enc *Encoding) frame(src []byte) (start, end int) {
+	start = len(src) - len(bytes.TrimLeft(src, " \t\r\n\f\v"))
+	if bytes.HasPrefix(src[start:], []byte(enc.prefix)) {
+		start += len(enc.prefix)
+	} else {
+		start = 0
+	}
+	end = bytes.Index(src[start:], []byte(enc.suffix))
+	if end >= 0 {
+		end += start
+	}
+	return start, end
+}
+
+// decodeBlocks decodes src into dst without framing.
+func (enc *Encoding) decodeBlocks(dst, src []byte) (ndst, nsrc int, err error) {
 	di := 0
 	si := 0
 
-	// SIMD fast path: process runs of 80 valid r85 bytes.
+	// SIMD fast path: process runs of 80 valid characters.
 	if haveSIMD {
+		var tmp [80]byte
 		for di+64 <= len(dst) && si+80 <= len(src) {
-			if !allValidR85(src[si : si+80]) {
+			in := src[si : si+80]
+			if enc.remap {
+				// Non-digits map to 0, and so do all bytes from 0x80 up
+				// in the kernel, so alphabets that use them take the
+				// slower loop.
+				if remapBlocksSIMD(&tmp[0], &in[0], &enc.toRows) != 0 && !enc.remapBlock(&tmp, in) {
+					break
+				}
+				in = tmp[:]
+			} else if !allValidR85(in) {
 				break
 			}
-			ovf := decodeBlocksSIMD(&dst[di], &src[si])
+			ovf := decodeBlocksSIMD(&dst[di], &in[0])
 			if ovf != 0 {
 				// Overflow detected; fall through to scalar for error reporting.
 				break
@@ -202,12 +463,71 @@ func Decode(dst, src []byte) (ndst, nsrc int, err error) {
 	// Collect valid characters into a block buffer.
 	var block [5]byte
 	bi := 0
+	run := false // whether a zero run marker awaits its count digit
+	bs := 0      // offset of the current block or zero run marker
 
 	for si < len(src) {
-		v, ok := decByte(src[si])
+		v := enc.decodeMap[src[si]]
 		si++
-		if ok == 0 {
+		switch {
+		case v < 85 && run:
+			n := 4 * (int(v) + minZeroRun)
+			if di+n > len(dst) {
+				return len(dst), si, nil
+			}
+			clear(dst[di : di+n])
+			di += n
+			run = false
 			continue
+		case v < 85:
+			if bi == 0 {
+				bs = si - 1
+			}
+		case v == digitSkip:
+			continue
+		case run:
+			return di, si, CorruptInputError{Reason: "zero run marker without a count", Offset: bs}
+		case v == digitSegment:
+			// The segment ends, so any block is its short final block.
+			if bi > 0 {
+				if di+bi-1 > len(dst) {
+					return len(dst), si, nil
+				}
+				if err := enc.decodeTail(dst[di:], block[:bi], bs); err != nil {
+					return di, si, err
+				}
+				di += bi - 1
+				bi = 0
+			}
+			continue
+		case v == digitZeroRun:
+			if bi != 0 {
+				return di, si, CorruptInputError{Reason: "shortcut character inside a block", Offset: bs}
+			}
+			run, bs = true, si-1
+			continue
+		case v == digitZero || v == digitSpaces:
+			if bi != 0 {
+				return di, si, CorruptInputError{Reason: "shortcut character inside a block", Offset: bs}
+			}
+			if di+4 > len(dst) {
+				return len(dst), si, nil
+			}
+			fill := byte(0)
+			if v == digitSpaces {
+				fill = ' '
+			}
+			dst[di+0] = fill
+			dst[di+1] = fill
+			dst[di+2] = fill
+			dst[di+3] = fill
+			di += 4
+			continue
+		default:
+			if bi == 0 {
+				bs = si - 1
+			}
+			return di, si, CorruptInputError{Reason: "reserved character", Offset: bs}
 		}
 		block[bi] = v
 		bi++
@@ -226,7 +546,10 @@ func Decode(dst, src []byte) (ndst, nsrc int, err error) {
 		acc = acc*85 + uint64(block[3])
 		acc = acc*85 + uint64(block[4])
 		if acc > 0xFFFFFFFF {
-			return di, si, CorruptInputError{"value overflow in 5-character block"}
+			if enc.partial == PartialPadded {
+				return enc.decodePadded(dst, di, src, si, acc, bs)
+			}
+			return di, si, CorruptInputError{Reason: "value overflow in 5-character block", Offset: bs}
 		}
 		dst[di+0] = byte(acc >> 24)
 		dst[di+1] = byte(acc >> 16)
@@ -237,69 +560,154 @@ func Decode(dst, src []byte) (ndst, nsrc int, err error) {
 	}
 
 	// Handle trailing block.
-	switch bi {
-	case 0:
+	if run {
+		return di, si, CorruptInputError{Reason: "zero run marker without a count", Offset: bs}
+	}
+	if bi == 0 {
 		return di, si, nil
-	case 1:
-		return di, si, CorruptInputError{"incomplete block: single trailing character"}
-	case 2: // 2 chars -> 1 byte
-		if di+1 > len(dst) {
-			return len(dst), si, nil
-		}
-		acc := uint32(block[0])*85 + uint32(block[1])
-		if acc > 0xFF {
-			return di, si, CorruptInputError{"value overflow in trailing block"}
-		}
-		dst[di] = byte(acc)
-		di++
-	case 3: // 3 chars -> 2 bytes
-		if di+2 > len(dst) {
-			return len(dst), si, nil
-		}
-		acc := (uint32(block[0])*85+uint32(block[1]))*85 + uint32(block[2])
-		if acc > 0xFFFF {
-			return di, si, CorruptInputError{"value overflow in trailing block"}
+	}
+	if di+bi-1 > len(dst) {
+		return len(dst), si, nil
+	}
+	if err := enc.decodeTail(dst[di:], block[:bi], bs); err != nil {
+		return di, si, err
+	}
+	return di + bi - 1, si, nil
+}
+
+// decodeTail decodes the short final block of digits in block, which
+// starts at offset bs, into the first len(block)-1 bytes of dst.
+func (enc *Encoding) decodeTail(dst, block []byte, bs int) error {
+	if len(block) == 1 {
+		return CorruptInputError{Reason: "incomplete block: single trailing character", Offset: bs}
+	}
+	if enc.partial == PartialNone || enc.partial == PartialPadded {
+		return CorruptInputError{Reason: "incomplete block: digit count is not a multiple of 5", Offset: bs}
+	}
+	n := len(block) - 1 // 2, 3 or 4 chars -> 1, 2 or 3 bytes
+	var acc uint64
+	for _, v := range block {
+		acc = acc*85 + uint64(v)
+	}
+	switch enc.partial {
+	case PartialValue:
+		if acc >= 1<<(8*n) {
+			return CorruptInputError{Reason: "value overflow in trailing block", Offset: bs}
 		}
-		dst[di+0] = byte(acc >> 8)
-		dst[di+1] = byte(acc)
-		di += 2
-	case 4: // 4 chars -> 3 bytes
-		if di+3 > len(dst) {
-			return len(dst), si, nil
+	case PartialTruncate, PartialZeroPad:
+		// Pad with the highest digit so that truncation rounds down to
+		// the encoded value.
+		for range 4 - n {
+			acc = acc*85 + 84
 		}
-		acc := ((uint32(block[0])*85+uint32(block[1]))*85+uint32(block[2]))*85 + uint32(block[3])
-		if acc > 0xFFFFFF {
-			return di, si, CorruptInputError{"value overflow in trailing block"}
+		if acc > 0xFFFFFFFF {
+			return CorruptInputError{Reason: "value overflow in trailing block", Offset: bs}
 		}
-		dst[di+0] = byte(acc >> 16)
-		dst[di+1] = byte(acc >> 8)
-		dst[di+2] = byte(acc)
-		di += 3
+		acc >>= 8 * (4 - n)
 	}
+	for i := n - 1; i >= 0; i-- {
+		dst[i] = byte(acc)
+		acc >>= 8
+	}
+	return nil
+}
 
-	return di, si, nil
+// paddedBase is the value of a 5-character block that holds a final
+// block of one byte under [PartialPadded].  Values from 2^32 upward are
+// not valid for full blocks.
+const paddedBase = 1 << 32
+
+// decodePadded decodes the padded final block with value acc, which
+// spans src[bs:si], into dst[di:].  The rest of src, or of the segment,
+// must not contain any more digits or shortcuts.
+func (enc *Encoding) decodePadded(dst []byte, di int, src []byte, si int, acc uint64, bs int) (ndst, nsrc int, err error) {
+	acc -= paddedBase
+	n := int(acc>>24) + 1 // 1, 2 or 3 bytes
+	if n > 3 || acc&0xFFFFFF >= 1<<(8*n) {
+		return di, si, CorruptInputError{Reason: "value overflow in 5-character block", Offset: bs}
+	}
+	end := len(src)
+	for i, c := range src[si:] {
+		if v := enc.decodeMap[c]; v == digitSegment {
+			end = si + i
+			break
+		} else if v != digitSkip {
+			return di, si, CorruptInputError{Reason: "data after padded final block", Offset: bs}
+		}
+	}
+	if di+n > len(dst) {
+		return len(dst), si, nil
+	}
+	for i := n - 1; i >= 0; i-- {
+		dst[di+i] = byte(acc)
+		acc >>= 8
+	}
+	if end == len(src) {
+		return di + n, len(src), nil
+	}
+	// Decode the segments after the delimiter.
+	m, k, err := enc.decodeBlocks(dst[di+n:], src[end+1:])
+	return di + n + m, end + 1 + k, shiftOffset(err, end+1)
 }
 
 // NewEncoder wraps a buffer and io.WriteCloser interface around Encode.
 // This will only write a short block (less than 4 bytes of binary input)
 // when Close is called.
 func NewEncoder(w io.Writer) io.WriteCloser {
-	return &encoder{w: w}
+	return StdEncoding.NewEncoder(w)
+}
+
+// A Flusher is an io.WriteCloser that can write out its buffered input
+// before it is closed.  The encoders returned by [NewEncoder] and
+// [Encoding.NewEncoder] implement it.
+type Flusher interface {
+	io.WriteCloser
+	// Flush writes all complete blocks to the underlying writer.  If the
+	// encoding has a segment delimiter, Flush also ends the current
+	// segment, so every byte written so far is sent: see
+	// [Encoding.WithSegments].
+	Flush() error
+}
+
+// NewEncoder wraps a buffer and io.WriteCloser interface around
+// enc.Encode.  Any framing prefix is written with the first output,
+// and the suffix is written by Close.  If enc uses [PartialNone], Close
+// returns [ErrPartialBlock] if the total input was not a multiple of 4
+// bytes.
+//
+// The encoder also implements [Flusher].
+func (enc *Encoding) NewEncoder(w io.Writer) io.WriteCloser {
+	e := &encoder{enc: enc, w: w}
+	if enc.groups > 0 {
+		// Start the first group after the prefix.
+		e.lines = &lineWriter{w: w, width: enc.groups, col: -len(enc.prefix), sep: []byte(enc.sep)}
+	}
+	return e
 }
 
 type encoder struct {
-	w   io.Writer
-	buf [4]byte
-	n   int
-	out [4096]byte
-	on  int
-	err error
+	enc     *Encoding
+	w       io.Writer
+	lines   *lineWriter // inserts group separators, or nil
+	started bool
+	open    bool // whether the current segment has any input
+	buf     [4]byte
+	n       int
+	zeros   int // all-zero blocks held back: see encodeStream
+	out     [4096]byte
+	on      int
+	err     error
 }
 
 func (e *encoder) Write(p []byte) (int, error) {
 	if e.err != nil {
 		return 0, e.err
 	}
+	if !e.started {
+		e.started = true
+		e.on += copy(e.out[e.on:], e.enc.prefix)
+	}
+	e.open = e.open || len(p) > 0
 	written := 0
 
 	// If we have pending bytes, fill up to a 4-byte block.
@@ -313,29 +721,19 @@ func (e *encoder) Write(p []byte) (int, error) {
 		if e.n < 4 {
 			return written, nil
 		}
-		e.on += Encode(e.out[e.on:], e.buf[:])
+		if e.err = e.encode(e.buf[:]); e.err != nil {
+			return written, e.err
+		}
 		e.n = 0
 	}
 
 	// Encode directly from p into the output buffer, flushing as needed.
-	for len(p) >= 4 {
-		if e.on+5 > len(e.out) {
-			if e.err = e.flush(); e.err != nil {
-				return written, e.err
-			}
-		}
-		// Encode as many full blocks as fit in the remaining output buffer.
-		// Each 4 input bytes produce 5 output bytes.
-		outAvail := len(e.out) - e.on
-		maxIn := (outAvail / 5) * 4
-		if maxIn > len(p) {
-			// Round down to a 4-byte boundary so we only encode full blocks.
-			maxIn = len(p) &^ 3
-		}
-		e.on += Encode(e.out[e.on:], p[:maxIn])
-		p = p[maxIn:]
-		written += maxIn
+	k := len(p) &^ 3
+	if e.err = e.encode(p[:k]); e.err != nil {
+		return written, e.err
 	}
+	p = p[k:]
+	written += k
 
 	// Buffer remaining 0–3 bytes.
 	for len(p) > 0 {
@@ -348,22 +746,111 @@ func (e *encoder) Write(p []byte) (int, error) {
 	return written, nil
 }
 
+// encode encodes the full blocks of p into the output buffer, flushing
+// it as needed.
+func (e *encoder) encode(p []byte) error {
+	for len(p) >= 4 {
+		if e.on+10 > len(e.out) {
+			if err := e.flush(); err != nil {
+				return err
+			}
+		}
+		n, m := e.enc.encodeStream(e.out[e.on:], p, &e.zeros)
+		e.on += n
+		p = p[m:]
+	}
+	return nil
+}
+
+// endZeros encodes the all-zero blocks held back by encode.
+func (e *encoder) endZeros() error {
+	for e.zeros > 0 {
+		if e.on+10 > len(e.out) {
+			if err := e.flush(); err != nil {
+				return err
+			}
+		}
+		e.on += e.enc.endZeros(e.out[e.on:], &e.zeros)
+	}
+	return nil
+}
+
 func (e *encoder) flush() error {
-	if e.on > 0 {
+	if e.on > 0 && e.lines != nil {
+		_, e.err = e.lines.Write(e.out[:e.on])
+		e.on = 0
+	} else if e.on > 0 {
 		_, e.err = e.w.Write(e.out[:e.on])
 		e.on = 0
 	}
 	return e.err
 }
 
+// Flush writes the encoded text so far to the underlying writer.  If the
+// encoding has segments, it first ends the current segment, unless it is
+// empty: it encodes any remaining 1–3 bytes as a short block and writes
+// the delimiter.
+func (e *encoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.err = e.endZeros(); e.err != nil {
+		return e.err
+	}
+	if e.enc.segment != 0 && e.open {
+		if e.on+6 > len(e.out) {
+			if e.err = e.flush(); e.err != nil {
+				return e.err
+			}
+		}
+		if e.n > 0 {
+			if e.enc.partial == PartialNone {
+				e.err = ErrPartialBlock
+				return e.err
+			}
+			e.on += e.enc.encodeBlocks(e.out[e.on:], e.buf[:e.n])
+			e.n = 0
+		}
+		e.out[e.on] = e.enc.segment
+		e.on++
+		e.open = false
+	}
+	return e.flush()
+}
+
 func (e *encoder) Close() error {
 	if e.err != nil {
 		return e.err
 	}
+	if !e.started {
+		e.started = true
+		e.on += copy(e.out[e.on:], e.enc.prefix)
+	}
+	if e.err = e.endZeros(); e.err != nil {
+		return e.err
+	}
+	if e.on+5+len(e.enc.suffix) > len(e.out) {
+		if e.err = e.flush(); e.err != nil {
+			return e.err
+		}
+	}
 	if e.n > 0 {
-		e.on += Encode(e.out[e.on:], e.buf[:e.n])
+		if e.enc.partial == PartialNone {
+			e.err = ErrPartialBlock
+			return e.err
+		}
+		e.on += e.enc.encodeBlocks(e.out[e.on:], e.buf[:e.n])
 		e.n = 0
 	}
+	if e.lines != nil && e.enc.suffix != "" {
+		// The suffix is not part of any group.
+		if e.err = e.flush(); e.err != nil {
+			return e.err
+		}
+		_, e.err = io.WriteString(e.w, e.enc.suffix)
+		return e.err
+	}
+	e.on += copy(e.out[e.on:], e.enc.suffix)
 	return e.flush()
 }
 
@@ -375,17 +862,58 @@ func (e *encoder) Close() error {
 // and only treats them as a final partial block when the underlying
 // reader returns io.EOF (or another error).  A single trailing r85
 // digit at true EOF is reported as a CorruptInputError.
-func NewDecoder(r io.Reader) io.Reader {
-	return &decoder{r: r}
+func NewDecoder(r io.Reader, opts ...DecoderOption) io.Reader {
+	return StdEncoding.NewDecoder(r, opts...)
 }
 
+// NewDecoder wraps a buffer and io.Reader interface around enc.Decode,
+// handling split blocks in the same way as the package-level [NewDecoder].
+// If enc has framing, the optional prefix is skipped and the decoder
+// stops after reading the suffix.  Errors are reported with offsets
+// counted from the start of the text after any prefix.
+func (enc *Encoding) NewDecoder(r io.Reader, opts ...DecoderOption) io.Reader {
+	d := &decoder{enc: enc, maxDecoded: -1, maxSkipped: -1, maxSkipRun: -1}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.maxSkipped >= 0 || d.maxSkipRun >= 0 {
+		// Count skipped bytes before the framing is removed, so that
+		// none go uncounted.
+		r = &skipLimiter{r: r, d: d}
+	}
+	if enc.suffix != "" {
+		r = &frameReader{r: r, prefix: enc.prefix, suffix: enc.suffix}
+	}
+	d.r = r
+	return d
+}
+
+// decoderBufSize is the size of the decoder's input buffer.
+const decoderBufSize = 1024
+
 type decoder struct {
+	enc    *Encoding
 	r      io.Reader
-	carry  [4]byte // up to 4 undecoded r85 digits carried across reads
-	cn     int     // number of carried digits
-	outbuf [816]byte
+	carry  [4]byte // up to 4 undecoded digits, or a zero run marker, carried across reads
+	cn     int     // number of carried bytes
+	outbuf []byte
 	out    []byte
+	final  bool // whether a padded final block has been decoded
+	pos    int  // offset in the input of the next byte to read from r
+	cpos   int  // offset in the input of the first carried byte
 	err    error
+
+	lenient bool // whether damaged blocks are recovered, per mode
+	mode    Recovery
+	errs    CorruptInputErrors
+
+	// Limits, or -1 for none, and the counts they apply to.
+	maxDecoded int64
+	maxSkipped int64
+	maxSkipRun int
+	decoded    int64
+	skipped    int64
+	skipRun    int
 }
 
 func (d *decoder) Read(p []byte) (int, error) {
@@ -400,81 +928,177 @@ func (d *decoder) Read(p []byte) (int, error) {
 	}
 
 	// Read encoded input into a temporary buffer, prepending any carry.
-	var inbuf [1024]byte
+	var inbuf [decoderBufSize]byte
 	copy(inbuf[:], d.carry[:d.cn])
 	nn, readErr := d.r.Read(inbuf[d.cn:])
 	total := d.cn + nn
+	cn, cpos, base := d.cn, d.cpos, d.pos-d.cn
 	d.cn = 0
+	d.pos += nn
+	// offset maps an index in inbuf to an offset in the input.  Carried
+	// digits are reported at the first of them, which starts a block.
+	offset := func(i int) int {
+		if i < cn {
+			return cpos
+		}
+		return base + i
+	}
 
 	if total == 0 {
 		if readErr != nil {
-			d.err = readErr
+			return 0, d.fail(readErr)
 		}
 		return 0, d.err
 	}
 
-	if readErr == nil {
-		// Not at EOF: keep a partial trailing block for next read.
-		// Count valid r85 characters in the tail to find how many
-		// to carry over.  We need to carry the last (validCount % 5)
-		// valid characters plus any trailing invalid characters after them.
-		// Simpler: scan backwards to find up to 4 valid trailing chars
-		// that don't form a complete block.
-
-		// Count total valid chars.
-		validCount := 0
-		for i := 0; i < total; i++ {
-			_, ok := decByte(inbuf[i])
-			if ok != 0 {
-				validCount++
+	// A skip limit ends the input, but the complete blocks before it are
+	// still returned, and the limit is reported by the next Read.
+	limit := readErr == ErrSkippedLimit || readErr == ErrSkipRunLimit
+	if limit {
+		d.err = readErr
+	}
+
+	if d.final {
+		// Only skipped characters may follow a padded final block.
+		for i, c := range inbuf[:total] {
+			if d.enc.decodeMap[c] != digitSkip {
+				d.err = CorruptInputError{Reason: "data after padded final block", Offset: offset(i)}
+				return 0, d.err
 			}
 		}
-		trailing := validCount % 5
-		if trailing > 0 {
-			// Walk backwards to find the start of the last `trailing` valid chars.
-			found := 0
-			carryStart := total
-			for i := total - 1; i >= 0 && found < trailing; i-- {
-				_, ok := decByte(inbuf[i])
-				if ok != 0 {
-					found++
-					carryStart = i
+		return 0, d.fail(readErr)
+	}
+
+	if readErr == nil || limit {
+		// Not at EOF: keep the digits of a partial trailing block for
+		// the next read.  A limit does not end the data, so they are
+		// never decoded.
+		cut, phase, run := d.enc.scanBlocks(inbuf[:total])
+		if run >= 0 {
+			d.carry[0] = inbuf[run]
+			d.cn = 1
+			d.cpos = offset(run)
+			total = cut
+		} else if phase > 0 {
+			for i, c := range inbuf[cut:total] {
+				if d.enc.decodeMap[c] < 85 {
+					if d.cn == 0 {
+						d.cpos = offset(cut + i)
+					}
+					d.carry[d.cn] = c
+					d.cn++
 				}
 			}
-			d.cn = copy(d.carry[:], inbuf[carryStart:total])
-			total = carryStart
+			total = cut
 		}
 	}
 
 	if total > 0 {
-		ndst, _, decErr := Decode(d.outbuf[:], inbuf[:total])
-		if decErr != nil {
-			d.err = decErr
-			if ndst == 0 {
-				return 0, d.err
+		if n := d.enc.decodedLen(inbuf[:total]); n > len(d.outbuf) {
+			d.outbuf = make([]byte, max(n, MaxDecodedLen(decoderBufSize)))
+		}
+		var ndst int
+		if d.lenient {
+			var errs CorruptInputErrors
+			ndst, _, errs = d.enc.decodeLenient(d.outbuf, inbuf[:total], d.mode)
+			for _, e := range errs {
+				e.Offset = offset(e.Offset)
+				d.errs = append(d.errs, e)
 			}
+		} else {
+			var decErr error
+			ndst, _, decErr = d.enc.decodeBlocks(d.outbuf, inbuf[:total])
+			if decErr != nil {
+				if ce, ok := decErr.(CorruptInputError); ok {
+					ce.Offset = offset(ce.Offset)
+					decErr = ce
+				}
+				d.err = decErr
+				if ndst == 0 {
+					return 0, d.err
+				}
+			}
+		}
+		if d.maxDecoded >= 0 && d.decoded+int64(ndst) > d.maxDecoded {
+			ndst = int(d.maxDecoded - d.decoded)
+			d.err = ErrDecodedLimit
 		}
+		d.decoded += int64(ndst)
 		d.out = d.outbuf[:ndst]
+		// Only a padded final block decodes to a partial word before EOF.
+		// Lenient decoding can also leave one after a damaged block, and
+		// a segment can end with one.
+		d.final = !d.lenient && d.enc.segment == 0 && ndst%4 != 0
 		n := copy(p, d.out)
 		d.out = d.out[n:]
 		if readErr != nil && len(d.out) == 0 && d.err == nil {
-			d.err = readErr
+			d.fail(readErr)
 		}
 		return n, nil
 	}
 
 	// total == 0 but readErr == nil shouldn't happen, but handle gracefully.
 	if readErr != nil {
-		d.err = readErr
+		d.fail(readErr)
 	}
 	return 0, d.err
 }
 
+// scanBlocks finds where src can be split without cutting a block or a
+// zero run in two.  cut is the end of the last complete block, shortcut
+// or error; phase is the number of digits after it, and run is the index
+// of a zero run marker that awaits its count, or -1.
+func (enc *Encoding) scanBlocks(src []byte) (cut, phase, run int) {
+	run = -1
+	for i, c := range src {
+		switch v := enc.decodeMap[c]; {
+		case v == digitSkip:
+		case run >= 0 && v < 85:
+			cut, run = i+1, -1
+		case v < 85:
+			phase++
+			if phase == 5 {
+				cut, phase = i+1, 0
+			}
+		case v == digitZeroRun && phase == 0 && run < 0:
+			run = i
+		case (v == digitZero || v == digitSpaces) && phase == 0 && run < 0:
+			cut = i + 1
+		case v == digitSegment && run < 0:
+			// A delimiter ends any short block.
+			cut, phase = i+1, 0
+		default:
+			// Let Decode report the error, and start afresh after it.
+			cut, phase, run = i+1, 0, -1
+		}
+	}
+	return cut, phase, run
+}
+
+// ErrPartialBlock reports input to a [PartialNone] encoding that is not
+// a multiple of 4 bytes long.  Encoders and [Encoding.CheckEncodeLen]
+// return it, and Encode panics with it.
+var ErrPartialBlock = errors.New("r85: input length is not a multiple of 4")
+
 // CorruptInputError is returned by [Decode] and [DecodeString] when the
 // input is not valid r85 text.
 type CorruptInputError struct {
 	// Reason describes why decoding failed.
 	Reason string
+	// Offset is the index in the input of the damaged block, or of the
+	// misplaced character if it is not within a block.  It is 0 where
+	// no position applies, as for a missing end delimiter.
+	Offset int
+}
+
+// shiftOffset adds off to the Offset of err, if it is a
+// CorruptInputError.
+func shiftOffset(err error, off int) error {
+	if ce, ok := err.(CorruptInputError); ok {
+		ce.Offset += off
+		return ce
+	}
+	return err
 }
 
 func (e CorruptInputError) Error() string {
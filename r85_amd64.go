@@ -6,7 +6,12 @@ import "golang.org/x/sys/cpu"
 
 const haveSIMD = true
 
-var haveAVX512 = cpu.X86.HasAVX512F && cpu.X86.HasAVX512BW && cpu.X86.HasAVX512VL
+// avx512Ready reports whether the AVX-512 kernels are implemented.
+// They are still stubs that write nothing, so the AVX2 kernels must be
+// used even on CPUs that support AVX-512.
+const avx512Ready = false
+
+var haveAVX512 = avx512Ready && cpu.X86.HasAVX512F && cpu.X86.HasAVX512BW && cpu.X86.HasAVX512VL
 
 //go:noescape
 func encodeBlocksAVX2(dst *byte, src *byte)
@@ -14,6 +19,12 @@ func encodeBlocksAVX2(dst *byte, src *byte)
 //go:noescape
 func decodeBlocksAVX2(dst *byte, src *byte) uint64
 
+//go:noescape
+func encodeBlocksRowsAVX2(dst *byte, src *byte, rows *[96]byte)
+
+//go:noescape
+func remapBlocksAVX2(dst *byte, src *byte, rows *[128]byte) uint64
+
 //go:noescape
 func encodeBlocksAVX512(dst *byte, src *byte)
 
@@ -34,3 +45,19 @@ func decodeBlocksSIMD(dst *byte, src *byte) uint64 {
 	}
 	return decodeBlocksAVX2(dst, src)
 }
+
+func encodeBlocksRowsSIMD(dst *byte, src *byte, rows *[96]byte) {
+	encodeBlocksRowsAVX2(dst, src, rows)
+}
+
+func remapBlocksSIMD(dst *byte, src *byte, rows *[128]byte) uint64 {
+	return remapBlocksAVX2(dst, src, rows)
+}
+
+// remapRows lays out a table for the AVX2 kernels, which XOR the lookups
+// in every row of 16 entries: each row is XORed with the row after it.
+func remapRows(rows []byte) {
+	for i := range len(rows) - 16 {
+		rows[i] ^= rows[i+16]
+	}
+}
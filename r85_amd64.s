@@ -111,6 +111,41 @@ DATA const86b<>+16(SB)/8, $0x5656565656565656
 DATA const86b<>+24(SB)/8, $0x5656565656565656
 GLOBL const86b<>(SB), NOPTR|RODATA, $32
 
+// Per-row bias for table lookups: 0x70 - 16*h for row h
+DATA rowBias<>+0(SB)/8, $0x7070707070707070
+DATA rowBias<>+8(SB)/8, $0x7070707070707070
+DATA rowBias<>+16(SB)/8, $0x7070707070707070
+DATA rowBias<>+24(SB)/8, $0x7070707070707070
+DATA rowBias<>+32(SB)/8, $0x6060606060606060
+DATA rowBias<>+40(SB)/8, $0x6060606060606060
+DATA rowBias<>+48(SB)/8, $0x6060606060606060
+DATA rowBias<>+56(SB)/8, $0x6060606060606060
+DATA rowBias<>+64(SB)/8, $0x5050505050505050
+DATA rowBias<>+72(SB)/8, $0x5050505050505050
+DATA rowBias<>+80(SB)/8, $0x5050505050505050
+DATA rowBias<>+88(SB)/8, $0x5050505050505050
+DATA rowBias<>+96(SB)/8, $0x4040404040404040
+DATA rowBias<>+104(SB)/8, $0x4040404040404040
+DATA rowBias<>+112(SB)/8, $0x4040404040404040
+DATA rowBias<>+120(SB)/8, $0x4040404040404040
+DATA rowBias<>+128(SB)/8, $0x3030303030303030
+DATA rowBias<>+136(SB)/8, $0x3030303030303030
+DATA rowBias<>+144(SB)/8, $0x3030303030303030
+DATA rowBias<>+152(SB)/8, $0x3030303030303030
+DATA rowBias<>+160(SB)/8, $0x2020202020202020
+DATA rowBias<>+168(SB)/8, $0x2020202020202020
+DATA rowBias<>+176(SB)/8, $0x2020202020202020
+DATA rowBias<>+184(SB)/8, $0x2020202020202020
+DATA rowBias<>+192(SB)/8, $0x1010101010101010
+DATA rowBias<>+200(SB)/8, $0x1010101010101010
+DATA rowBias<>+208(SB)/8, $0x1010101010101010
+DATA rowBias<>+216(SB)/8, $0x1010101010101010
+DATA rowBias<>+224(SB)/8, $0x0000000000000000
+DATA rowBias<>+232(SB)/8, $0x0000000000000000
+DATA rowBias<>+240(SB)/8, $0x0000000000000000
+DATA rowBias<>+248(SB)/8, $0x0000000000000000
+GLOBL rowBias<>(SB), NOPTR|RODATA, $256
+
 // Decode-only masks (16 bytes, used with XMM only)
 DATA decShufMain<>+0(SB)/8, $0x800B06010F0A0500
 DATA decShufMain<>+8(SB)/8, $0x800D0803800C0702
@@ -185,9 +220,76 @@ GLOBL maskEven<>(SB), NOPTR|RODATA, $32
 	VPAND	const30b<>(SB), X2, X2;              \
 	VPSUBB	X2, X_data, X_data
 
+// REMAP_ROW_YMM: look up the bytes of Y0-Y2 in table row h (at DX),
+// and XOR the entries into Y4-Y6.  Clobbers Y3, Y7.
+#define REMAP_ROW_YMM(h) \
+	VBROADCASTI128	(16*h)(DX), Y3;               \
+	VPADDUSB	rowBias<>+(32*h)(SB), Y0, Y7; \
+	VPSHUFB	Y7, Y3, Y7;                          \
+	VPXOR	Y7, Y4, Y4;                          \
+	VPADDUSB	rowBias<>+(32*h)(SB), Y1, Y7; \
+	VPSHUFB	Y7, Y3, Y7;                          \
+	VPXOR	Y7, Y5, Y5;                          \
+	VPADDUSB	rowBias<>+(32*h)(SB), Y2, Y7; \
+	VPSHUFB	Y7, Y3, Y7;                          \
+	VPXOR	Y7, Y6, Y6
+
+// ENCODE_DIGITS_YMM: load 32 bytes from SI and convert them to 40
+// digits in stride-5 order: the first 16 of each lane's 20 digits in Y5,
+// and the last 4 in Y14.  Clobbers Y0-Y10.  Uses Y11-Y13.
+#define ENCODE_DIGITS_YMM \
+	VMOVDQU	(SI), Y0;                            \
+	VPSHUFB	Y11, Y0, Y0;                         \
+	DIV85_YMM(Y0, Y4, Y6);                       \
+	DIV85_YMM(Y4, Y5, Y7);                       \
+	DIV85_YMM(Y5, Y4, Y8);                       \
+	DIV85_YMM(Y4, Y9, Y10);                      \
+	VPACKUSDW	Y10, Y9, Y0;                 \
+	VPACKUSDW	Y7, Y8, Y4;                  \
+	VPACKUSWB	Y4, Y0, Y0;                  \
+	VPSHUFB	packLow<>(SB), Y6, Y4;               \
+	VPSHUFB	encShufMain<>(SB), Y0, Y5;           \
+	VPSHUFB	encShufD4<>(SB), Y4, Y14;            \
+	VPOR	Y5, Y14, Y5;                         \
+	VPSHUFB	encShufHi<>(SB), Y0, Y14;            \
+	VPSHUFB	encShufD4Hi<>(SB), Y4, Y6;           \
+	VPOR	Y14, Y6, Y14
+
+// STORE_CHARS_YMM: store the 40 characters in Y_main and Y_tail, laid
+// out as ENCODE_DIGITS_YMM leaves the digits, to DI.
+#define STORE_CHARS_YMM(Y_main, Y_tail, X_main, X_tail) \
+	MOVOU	X_main, (DI);                        \
+	MOVL	X_tail, 16(DI);                      \
+	VEXTRACTI128	$1, Y_main, X_main;          \
+	VEXTRACTI128	$1, Y_tail, X_tail;          \
+	MOVOU	X_main, 20(DI);                      \
+	MOVL	X_tail, 36(DI)
+
+// DIGIT_ROW_YMM: look up the digits of Y_in in alphabet row h (at DX),
+// as REMAP_ROW_YMM does, and XOR the entries into Y_out.  Clobbers Y0,
+// Y1.
+#define DIGIT_ROW_YMM(h, Y_in, Y_out) \
+	VBROADCASTI128	(16*h)(DX), Y0;               \
+	VPADDUSB	rowBias<>+(32*h)(SB), Y_in, Y1; \
+	VPSHUFB	Y1, Y0, Y1;                          \
+	VPXOR	Y1, Y_out, Y_out
+
+// DIGITS_TO_CHARS_YMM: map the digits of Y_in to characters in Y_out
+// through the alphabet at DX.  Clobbers Y0, Y1.
+#define DIGITS_TO_CHARS_YMM(Y_in, Y_out) \
+	VPXOR	Y_out, Y_out, Y_out;                 \
+	DIGIT_ROW_YMM(0, Y_in, Y_out);               \
+	DIGIT_ROW_YMM(1, Y_in, Y_out);               \
+	DIGIT_ROW_YMM(2, Y_in, Y_out);               \
+	DIGIT_ROW_YMM(3, Y_in, Y_out);               \
+	DIGIT_ROW_YMM(4, Y_in, Y_out);               \
+	DIGIT_ROW_YMM(5, Y_in, Y_out)
+
 // ===== encodeBlocksAVX2 =====
 // func encodeBlocksAVX2(dst *byte, src *byte)
 // 2 iterations x 8 uint32 lanes (YMM). 64 bytes in -> 80 bytes out.
+// Each lane holds 4 groups: the low lane is stored first (20 bytes),
+// then the high lane.
 TEXT ·encodeBlocksAVX2(SB), NOSPLIT|NOFRAME, $0-16
 	MOVQ	dst+0(FP), DI
 	MOVQ	src+8(FP), SI
@@ -199,44 +301,13 @@ TEXT ·encodeBlocksAVX2(SB), NOSPLIT|NOFRAME, $0-16
 	MOVQ	$2, CX
 
 enc_loop:
-	// Load 32 bytes, byte-swap to big-endian uint32s
-	VMOVDQU	(SI), Y0
-	VPSHUFB	Y11, Y0, Y0
-
-	// 4 rounds of div-85: value -> (d0, d1, d2, d3, d4)
-	DIV85_YMM(Y0, Y4, Y6)      // d4=Y6, q=Y4
-	DIV85_YMM(Y4, Y5, Y7)      // d3=Y7, q=Y5
-	DIV85_YMM(Y5, Y4, Y8)      // d2=Y8, q=Y4
-	DIV85_YMM(Y4, Y9, Y10)     // d1=Y10, d0=Y9
-
-	// Pack uint32 digits to bytes (lane-local packing)
-	VPACKUSDW	Y10, Y9, Y0    // [d0,d1] per lane as uint16
-	VPACKUSDW	Y7, Y8, Y4     // [d2,d3] per lane as uint16
-	VPACKUSWB	Y4, Y0, Y0     // [d0..d3] per lane as bytes
-
-	// Pack d4: extract low byte of each uint32
-	VPSHUFB	packLow<>(SB), Y6, Y4
-
-	// Stride-5 interleave (lane-local, same masks both lanes)
-	VPSHUFB	encShufMain<>(SB), Y0, Y5
-	VPSHUFB	encShufD4<>(SB), Y4, Y14
-	VPOR	Y5, Y14, Y5              // first 16 output bytes per lane
-
-	VPSHUFB	encShufHi<>(SB), Y0, Y14
-	VPSHUFB	encShufD4Hi<>(SB), Y4, Y6
-	VPOR	Y14, Y6, Y14             // last 4 output bytes per lane
+	ENCODE_DIGITS_YMM
 
 	// Digit-to-char conversion (YMM, all 32 bytes at once)
 	DIGIT_TO_CHAR_YMM(Y5)
 	DIGIT_TO_CHAR_YMM(Y14)
 
-	// Store: low lane = groups 0-3 (20 bytes), high lane = groups 4-7 (20 bytes)
-	MOVOU	X5, (DI)
-	MOVL	X14, 16(DI)
-	VEXTRACTI128	$1, Y5, X5
-	VEXTRACTI128	$1, Y14, X14
-	MOVOU	X5, 20(DI)
-	MOVL	X14, 36(DI)
+	STORE_CHARS_YMM(Y5, Y14, X5, X14)
 
 	ADDQ	$32, SI
 	ADDQ	$40, DI
@@ -246,6 +317,49 @@ enc_loop:
 	VZEROUPPER
 	RET
 
+// ===== encodeBlocksRowsAVX2 =====
+// func encodeBlocksRowsAVX2(dst *byte, src *byte, rows *[96]byte)
+// As encodeBlocksAVX2, but maps digits to characters through an
+// alphabet laid out by remapRows.  The loop is unrolled so that the last
+// 4 digits of each lane in both halves share one lookup.
+TEXT ·encodeBlocksRowsAVX2(SB), NOSPLIT|NOFRAME, $0-24
+	MOVQ	dst+0(FP), DI
+	MOVQ	src+8(FP), SI
+	MOVQ	rows+16(FP), DX
+
+	VMOVDQU	bswap32<>(SB), Y11
+	VMOVDQU	magic85<>(SB), Y12
+	VMOVDQU	const85d<>(SB), Y13
+
+	// First 32 bytes: keep the last digits of each lane in Y15.
+	ENCODE_DIGITS_YMM
+	VMOVDQU	Y14, Y15
+	DIGITS_TO_CHARS_YMM(Y5, Y2)
+	MOVOU	X2, (DI)
+	VEXTRACTI128	$1, Y2, X2
+	MOVOU	X2, 20(DI)
+
+	// Second 32 bytes: move their last digits to bytes 4-7 of each lane.
+	ADDQ	$32, SI
+	ENCODE_DIGITS_YMM
+	VPSLLDQ	$4, Y14, Y14
+	VPOR	Y15, Y14, Y14
+	DIGITS_TO_CHARS_YMM(Y5, Y2)
+	DIGITS_TO_CHARS_YMM(Y14, Y3)
+	MOVOU	X2, 40(DI)
+	VEXTRACTI128	$1, Y2, X2
+	MOVOU	X2, 60(DI)
+
+	// Store the last 4 characters of each group of 20.
+	VEXTRACTI128	$1, Y3, X4
+	MOVL	X3, 16(DI)
+	VPEXTRD	$1, X3, 56(DI)
+	MOVL	X4, 36(DI)
+	VPEXTRD	$1, X4, 76(DI)
+
+	VZEROUPPER
+	RET
+
 // ===== decodeBlocksAVX2 =====
 // func decodeBlocksAVX2(dst *byte, src *byte) uint64
 // 4 iterations x 4 uint32 lanes (XMM). 80 bytes in -> 64 bytes out.
@@ -340,6 +454,52 @@ dec_loop:
 	VZEROUPPER
 	RET
 
+// ===== remapBlocksAVX2 =====
+// func remapBlocksAVX2(dst *byte, src *byte, rows *[128]byte) uint64
+// Maps 80 bytes through a 128-entry table, laid out by remapRows as rows
+// of 16 entries, each XORed with the row after it.  For row h, adding
+// rowBias[h] with saturation sets bit 7 of bytes in earlier rows, which
+// VPSHUFB maps to 0, and keeps the low nibble of the others, so XORing
+// the lookups in every row leaves the entry of the byte's own row.
+// Bytes from 0x80 up map to 0.  Returns nonzero if any byte maps to 0.
+// dst may equal src.
+TEXT ·remapBlocksAVX2(SB), NOSPLIT|NOFRAME, $0-32
+	MOVQ	dst+0(FP), DI
+	MOVQ	src+8(FP), SI
+	MOVQ	rows+16(FP), DX
+
+	VMOVDQU	(SI), Y0
+	VMOVDQU	32(SI), Y1
+	VMOVDQU	48(SI), Y2
+	VPXOR	Y4, Y4, Y4
+	VPXOR	Y5, Y5, Y5
+	VPXOR	Y6, Y6, Y6
+
+	REMAP_ROW_YMM(0)
+	REMAP_ROW_YMM(1)
+	REMAP_ROW_YMM(2)
+	REMAP_ROW_YMM(3)
+	REMAP_ROW_YMM(4)
+	REMAP_ROW_YMM(5)
+	REMAP_ROW_YMM(6)
+	REMAP_ROW_YMM(7)
+
+	VMOVDQU	Y4, (DI)
+	VMOVDQU	Y5, 32(DI)
+	VMOVDQU	Y6, 48(DI)
+
+	// Report bytes that mapped to 0.
+	VPXOR	Y3, Y3, Y3
+	VPCMPEQB	Y3, Y4, Y4
+	VPCMPEQB	Y3, Y5, Y5
+	VPCMPEQB	Y3, Y6, Y6
+	VPOR	Y4, Y5, Y4
+	VPOR	Y4, Y6, Y4
+	VPMOVMSKB	Y4, AX
+	MOVQ	AX, ret+24(FP)
+	VZEROUPPER
+	RET
+
 // ===== AVX-512 stubs (not implemented) =====
 
 TEXT ·encodeBlocksAVX512(SB), NOSPLIT|NOFRAME, $0-16
@@ -10,5 +10,23 @@ func encodeBlocksNEON(dst *byte, src *byte)
 //go:noescape
 func decodeBlocksNEON(dst *byte, src *byte) uint64
 
+//go:noescape
+func encodeBlocksRowsNEON(dst *byte, src *byte, rows *[96]byte)
+
+//go:noescape
+func remapBlocksNEON(dst *byte, src *byte, rows *[128]byte) uint64
+
 func encodeBlocksSIMD(dst *byte, src *byte)       { encodeBlocksNEON(dst, src) }
 func decodeBlocksSIMD(dst *byte, src *byte) uint64 { return decodeBlocksNEON(dst, src) }
+
+func encodeBlocksRowsSIMD(dst *byte, src *byte, rows *[96]byte) {
+	encodeBlocksRowsNEON(dst, src, rows)
+}
+
+func remapBlocksSIMD(dst *byte, src *byte, rows *[128]byte) uint64 {
+	return remapBlocksNEON(dst, src, rows)
+}
+
+// remapRows lays out a table for the NEON kernels, which look up whole
+// tables at once, so it is left as it is.
+func remapRows(rows []byte) {}
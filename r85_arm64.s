@@ -70,6 +70,61 @@ DATA decDeinterleaveHi<>+0(SB)/8, $0x00000000130E0904
 DATA decDeinterleaveHi<>+8(SB)/8, $0x0000000000000000
 GLOBL decDeinterleaveHi<>(SB), NOPTR|RODATA, $16
 
+// ENCODE_DIGITS_NEON: load 16 bytes from R1 and convert them to 20
+// digits in stride-5 order: the first 16 in V13 and the last 4 in V14.
+// Each 4-byte group is divided by 85 four times, with the magic
+// multiplier in V26 and 85 in V27; the digits are narrowed to bytes and
+// interleaved with the tables in V28 and V29.  Clobbers V0-V14.
+#define ENCODE_DIGITS_NEON \
+	VLD1.P	16(R1), [V0.B16]; \
+	VREV32	V0.B16, V0.B16; \
+	VUMULL(6, 0, 26); \
+	VUMULL2(7, 0, 26); \
+	VUZP2	V7.S4, V6.S4, V1.S4; \
+	VUSHR	$6, V1.S4, V1.S4; \
+	VMOV	V0.B16, V5.B16; \
+	VMLS_S4(5, 1, 27); \
+	VUMULL(6, 1, 26); \
+	VUMULL2(7, 1, 26); \
+	VUZP2	V7.S4, V6.S4, V2.S4; \
+	VUSHR	$6, V2.S4, V2.S4; \
+	VMOV	V1.B16, V4.B16; \
+	VMLS_S4(4, 2, 27); \
+	VUMULL(6, 2, 26); \
+	VUMULL2(7, 2, 26); \
+	VUZP2	V7.S4, V6.S4, V3.S4; \
+	VUSHR	$6, V3.S4, V3.S4; \
+	VMOV	V2.B16, V8.B16; \
+	VMLS_S4(8, 3, 27); \
+	VUMULL(6, 3, 26); \
+	VUMULL2(7, 3, 26); \
+	VUZP2	V7.S4, V6.S4, V9.S4; \
+	VUSHR	$6, V9.S4, V9.S4; \
+	VMOV	V3.B16, V10.B16; \
+	VMLS_S4(10, 9, 27); \
+	VXTN_SH(9, 9); \
+	VXTN2_SH(9, 10); \
+	VXTN_HB(9, 9); \
+	VXTN_SH(8, 8); \
+	VXTN2_SH(8, 4); \
+	VXTN_HB(8, 8); \
+	VXTN_SH(5, 5); \
+	VXTN_HB(5, 5); \
+	VZIP1	V8.D2, V9.D2, V11.D2; \
+	VMOV	V5.B16, V12.B16; \
+	VTBL	V28.B16, [V11.B16, V12.B16], V13.B16; \
+	VTBL	V29.B16, [V11.B16, V12.B16], V14.B16
+
+// REMAP_NEON: map the bytes of in through the table in V16-V23 into
+// out, and accumulate a mask of the bytes that map to 0 in V26.
+// Clobbers V27.  Uses V24 (64) and V25 (zero).
+#define REMAP_NEON(in, out) \
+	VTBL	in.B16, [V16.B16, V17.B16, V18.B16, V19.B16], out.B16; \
+	VSUB	V24.B16, in.B16, V27.B16; \
+	VTBX	V27.B16, [V20.B16, V21.B16, V22.B16, V23.B16], out.B16; \
+	VCMEQ	out.B16, V25.B16, V27.B16; \
+	VORR	V27.B16, V26.B16, V26.B16
+
 // func encodeBlocksNEON(dst *byte, src *byte)
 // Encodes 64 binary input bytes into 80 r85-encoded output bytes.
 TEXT ·encodeBlocksNEON(SB), NOSPLIT|NOFRAME, $0-16
@@ -99,77 +154,8 @@ TEXT ·encodeBlocksNEON(SB), NOSPLIT|NOFRAME, $0-16
 	MOVD	$4, R5
 
 enc_chunk:
-	// Load 16 input bytes and byte-swap to big-endian uint32s.
-	VLD1.P	16(R1), [V0.B16]
-	VREV32	V0.B16, V0.B16
-
-	// Division round 1: d4 = acc % 85, q0 = acc / 85
-	VUMULL(6, 0, 26)               // V6.2D = low pair * magic
-	VUMULL2(7, 0, 26)              // V7.2D = high pair * magic
-	VUZP2	V7.S4, V6.S4, V1.S4   // V1 = high 32 bits of each product
-	VUSHR	$6, V1.S4, V1.S4      // V1 = quotient (acc / 85)
-	VMOV	V0.B16, V5.B16         // V5 = acc (for remainder)
-	VMLS_S4(5, 1, 27)             // V5 = acc - quot*85 = d4
-
-	// Division round 2: d3 = q0 % 85, q1 = q0 / 85
-	VUMULL(6, 1, 26)
-	VUMULL2(7, 1, 26)
-	VUZP2	V7.S4, V6.S4, V2.S4
-	VUSHR	$6, V2.S4, V2.S4
-	VMOV	V1.B16, V4.B16
-	VMLS_S4(4, 2, 27)             // V4 = d3
-
-	// Division round 3: d2 = q1 % 85, q2 = q1 / 85
-	VUMULL(6, 2, 26)
-	VUMULL2(7, 2, 26)
-	VUZP2	V7.S4, V6.S4, V3.S4
-	VUSHR	$6, V3.S4, V3.S4
-	VMOV	V2.B16, V8.B16
-	VMLS_S4(8, 3, 27)             // V8 = d2
-
-	// Division round 4: d1 = q2 % 85, d0 = q2 / 85
-	VUMULL(6, 3, 26)
-	VUMULL2(7, 3, 26)
-	VUZP2	V7.S4, V6.S4, V9.S4
-	VUSHR	$6, V9.S4, V9.S4      // V9 = d0
-	VMOV	V3.B16, V10.B16
-	VMLS_S4(10, 9, 27)            // V10 = d1
-
-	// Now: V9=d0, V10=d1, V8=d2, V4=d3, V5=d4 (all .S4, values 0–84)
-
-	// Narrow 32-bit digits to bytes.
-	// Pair d0+d1: narrow S4→H4, combine into H8, then H8→B8
-	VXTN_SH(9, 9)                 // V9.4H = narrow d0
-	VXTN2_SH(9, 10)               // V9.8H = {d0[0-3], d1[0-3]}
-	VXTN_HB(9, 9)                 // V9.8B = {d0[0-3], d1[0-3]} as bytes
-
-	// Pair d2+d3:
-	VXTN_SH(8, 8)                 // V8.4H = narrow d2
-	VXTN2_SH(8, 4)                // V8.8H = {d2[0-3], d3[0-3]}
-	VXTN_HB(8, 8)                 // V8.8B = {d2[0-3], d3[0-3]} as bytes
-
-	// d4:
-	VXTN_SH(5, 5)                 // V5.4H = narrow d4
-	VXTN_HB(5, 5)                 // V5.8B = {d4[0-3], ?, ?, ?, ?}
-
-	// Pack d0-d3 (16 bytes) into V11, d4 (4 bytes) in V5
-	VZIP1	V8.D2, V9.D2, V11.D2  // V11 = {d0d1_low64, d2d3_low64}
-
-	// Wait — VZIP1 on .D2 interleaves 64-bit halves:
-	// V11.D[0] = V9.D[0] (d0d1 bytes), V11.D[1] = V8.D[0] (d2d3 bytes)
-	// So V11 = {d0[0],d0[1],d0[2],d0[3], d1[0],d1[1],d1[2],d1[3],
-	//           d2[0],d2[1],d2[2],d2[3], d3[0],d3[1],d3[2],d3[3]}
-
-	// Interleave digits into stride-5 output order via VTBL.
-	// V11 (16 bytes) + V5 (4 bytes) as two-register table for VTBL.
-	// VTBL with 2 table registers: table = {V11, V12}
-	// We need V5's data as V12 for the VTBL2 addressing to work.
-	VMOV	V5.B16, V12.B16
-
-	// First 16 output bytes
-	VTBL	V28.B16, [V11.B16, V12.B16], V13.B16
-	// Last 4 output bytes
-	VTBL	V29.B16, [V11.B16, V12.B16], V14.B16
+	// Load 16 bytes and convert them to 20 digits in V13 and V14.
+	ENCODE_DIGITS_NEON
 
 	// Digit-to-char conversion on first 16 bytes.
 	// Must do fixup BEFORE adding 40, since we compare digit values.
@@ -201,6 +187,87 @@ enc_chunk:
 
 	RET
 
+// func encodeBlocksRowsNEON(dst *byte, src *byte, rows *[96]byte)
+// As encodeBlocksNEON, but maps digits to characters through a padded
+// alphabet: digits 0-63 with VTBL on its first 64 bytes, and 64-84 with
+// VTBX on the rest, which leaves the other lanes alone.
+TEXT ·encodeBlocksRowsNEON(SB), NOSPLIT|NOFRAME, $0-24
+	MOVD	dst+0(FP), R0
+	MOVD	src+8(FP), R1
+	MOVD	rows+16(FP), R2
+
+	// Set up constants.
+	MOVD	$0xC0C0C0C1, R3
+	VDUP	R3, V26.S4
+	MOVD	$85, R3
+	VDUP	R3, V27.S4
+	MOVD	$encInterleaveLo<>(SB), R3
+	VLD1	(R3), [V28.B16]
+	MOVD	$encInterleaveHi<>(SB), R3
+	VLD1	(R3), [V29.B16]
+	// The alphabet: digits 0-63 in V20-V23, 64-95 in V24-V25.
+	VLD1.P	64(R2), [V20.B16, V21.B16, V22.B16, V23.B16]
+	VLD1	(R2), [V24.B16, V25.B16]
+	VMOVI	$64, V30.B16
+
+	MOVD	$4, R5
+
+enc_rows_chunk:
+	ENCODE_DIGITS_NEON
+
+	// Digit-to-char lookup on all 20 digits.
+	VTBL	V13.B16, [V20.B16, V21.B16, V22.B16, V23.B16], V15.B16
+	VSUB	V30.B16, V13.B16, V16.B16
+	VTBX	V16.B16, [V24.B16, V25.B16], V15.B16
+	VTBL	V14.B16, [V20.B16, V21.B16, V22.B16, V23.B16], V17.B16
+	VSUB	V30.B16, V14.B16, V16.B16
+	VTBX	V16.B16, [V24.B16, V25.B16], V17.B16
+
+	// Store 20 output bytes: 16 via VST1 + 4 via scalar.
+	VST1.P	[V15.B16], 16(R0)
+	VMOV	V17.S[0], R4
+	MOVW	R4, (R0)
+	ADD	$4, R0
+
+	SUB	$1, R5
+	CBNZ	R5, enc_rows_chunk
+
+	RET
+
+// func remapBlocksNEON(dst *byte, src *byte, rows *[128]byte) uint64
+// Maps 80 bytes through a 128-entry table: bytes 0-63 with VTBL on its
+// first 64 bytes, and 64-127 with VTBX on the rest.  Bytes from 0x80 up
+// are out of range for both, so they map to 0.  Returns nonzero if any
+// byte maps to 0.  dst may equal src.
+TEXT ·remapBlocksNEON(SB), NOSPLIT|NOFRAME, $0-32
+	MOVD	dst+0(FP), R0
+	MOVD	src+8(FP), R1
+	MOVD	rows+16(FP), R2
+
+	VLD1.P	64(R2), [V16.B16, V17.B16, V18.B16, V19.B16]
+	VLD1	(R2), [V20.B16, V21.B16, V22.B16, V23.B16]
+	VMOVI	$64, V24.B16
+	VEOR	V25.B16, V25.B16, V25.B16  // zero, to detect unmapped bytes
+	VEOR	V26.B16, V26.B16, V26.B16  // accumulator for unmapped bytes
+
+	VLD1.P	64(R1), [V0.B16, V1.B16, V2.B16, V3.B16]
+	VLD1	(R1), [V4.B16]
+
+	REMAP_NEON(V0, V5)
+	REMAP_NEON(V1, V6)
+	REMAP_NEON(V2, V7)
+	REMAP_NEON(V3, V8)
+	REMAP_NEON(V4, V9)
+
+	VST1.P	[V5.B16, V6.B16, V7.B16, V8.B16], 64(R0)
+	VST1	[V9.B16], (R0)
+
+	VMOV	V26.D[0], R2
+	VMOV	V26.D[1], R3
+	ORR	R2, R3, R2
+	MOVD	R2, ret+24(FP)
+	RET
+
 // func decodeBlocksNEON(dst *byte, src *byte) uint64
 // Decodes 80 valid r85-encoded bytes into 64 binary output bytes.
 // Returns 0 on success, nonzero if any group overflows uint32.
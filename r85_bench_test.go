@@ -3,6 +3,7 @@ package r85
 import (
 	"bytes"
 	"io"
+	"math/big"
 	"testing"
 )
 
@@ -226,3 +227,60 @@ func BenchmarkDecodeVsDecoder(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkEncodeZ85 benchmarks Encode with the remapped Z85 alphabet.
+func BenchmarkEncodeZ85(b *testing.B) {
+	for _, sz := range benchSizes {
+		src := makeSrc(sz.n)
+		dst := make([]byte, MaxEncodedLen(sz.n))
+		b.Run(sz.name, func(b *testing.B) {
+			b.SetBytes(int64(sz.n))
+			for b.Loop() {
+				Z85Encoding.Encode(dst, src)
+			}
+		})
+	}
+}
+
+// BenchmarkDecodeZ85 benchmarks Decode with the remapped Z85 alphabet.
+func BenchmarkDecodeZ85(b *testing.B) {
+	for _, sz := range benchSizes {
+		src := makeSrc(sz.n)
+		enc := make([]byte, MaxEncodedLen(sz.n))
+		Z85Encoding.Encode(enc, src)
+		dst := make([]byte, sz.n)
+		b.Run(sz.name, func(b *testing.B) {
+			b.SetBytes(int64(sz.n))
+			for b.Loop() {
+				Z85Encoding.Decode(dst, enc)
+			}
+		})
+	}
+}
+
+// BenchmarkEncodeBigInt benchmarks whole-number conversion of 1 KB to
+// 256 KB values.
+func BenchmarkEncodeBigInt(b *testing.B) {
+	for _, sz := range benchSizes[2:5] {
+		x := new(big.Int).SetBytes(makeSrc(sz.n))
+		b.Run(sz.name, func(b *testing.B) {
+			b.SetBytes(int64(sz.n))
+			for b.Loop() {
+				EncodeBigInt(x)
+			}
+		})
+	}
+}
+
+// BenchmarkDecodeBigInt benchmarks parsing of 1 KB to 256 KB values.
+func BenchmarkDecodeBigInt(b *testing.B) {
+	for _, sz := range benchSizes[2:5] {
+		s := EncodeBigInt(new(big.Int).SetBytes(makeSrc(sz.n)))
+		b.Run(sz.name, func(b *testing.B) {
+			b.SetBytes(int64(sz.n))
+			for b.Loop() {
+				DecodeBigInt(s)
+			}
+		})
+	}
+}
@@ -6,3 +6,7 @@ const haveSIMD = false
 
 func encodeBlocksSIMD(dst *byte, src *byte)       {}
 func decodeBlocksSIMD(dst *byte, src *byte) uint64 { return 1 }
+
+func encodeBlocksRowsSIMD(dst *byte, src *byte, rows *[96]byte)    {}
+func remapBlocksSIMD(dst *byte, src *byte, rows *[128]byte) uint64 { return 1 }
+func remapRows(rows []byte)                                        {}
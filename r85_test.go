@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"testing"
+	"testing/iotest"
 )
 
 // TestEncByteAlphabet verifies the full encoding alphabet.
@@ -452,3 +453,23 @@ func TestEncodeDecodeZerosAndMaxes(t *testing.T) {
 		})
 	}
 }
+
+// TestDecoderReaderSplitBlocks tests the streaming decoder when blocks
+// are split across reads and interleaved with skipped characters.
+func TestDecoderReaderSplitBlocks(t *testing.T) {
+	src := makeSrc(200)
+	var text []byte
+	for i, c := range []byte(EncodeToString(src)) {
+		text = append(text, c)
+		if i%3 == 2 {
+			text = append(text, "\r\n"...)
+		}
+	}
+	got, err := io.ReadAll(NewDecoder(iotest.HalfReader(bytes.NewReader(text))))
+	if err != nil {
+		t.Fatalf("ReadAll decoder: err = %v", err)
+	}
+	if !bytes.Equal(got, src) {
+		t.Errorf("decoder: roundtrip mismatch")
+	}
+}
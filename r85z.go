@@ -0,0 +1,9 @@
+package r85
+
+// R85ZEncoding is r85z, the variant of [StdEncoding] for sparse data.
+// '!' stands for an all-zero 4-byte block, and '#' followed by a digit d
+// stands for a run of d+3 all-zero blocks, so a run of 3 to 87 zero
+// blocks takes two characters.  The shortcuts are never used for a
+// partial block.  [StdEncoding] reports both characters as corrupt
+// input, so r85z text is never silently misread as r85.
+var R85ZEncoding = StdEncoding.WithZero('!').WithZeroRun('#')
@@ -0,0 +1,129 @@
+package r85
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestR85ZKnownValues tests the r85z shortcuts against known encodings.
+func TestR85ZKnownValues(t *testing.T) {
+	zeros := func(n int) string { return strings.Repeat("\x00", n) }
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{zeros(4), "!"},
+		{zeros(8), "!!"},
+		{zeros(12), "#("},
+		{zeros(13), "#((("},
+		{zeros(4 * 87), "#|"},
+		{zeros(4 * 88), "#|!"},
+		{zeros(4 * 90), "#|#("},
+		{zeros(3), "(((("},
+		{"\x00\x00\x00\x01" + zeros(4), "(((()!"},
+	}
+	for _, tt := range tests {
+		got := R85ZEncoding.EncodeToString([]byte(tt.in))
+		if got != tt.want {
+			t.Errorf("Encode(%d bytes) = %q, want %q", len(tt.in), got, tt.want)
+		}
+		dec, err := R85ZEncoding.DecodeString(tt.want)
+		if err != nil || string(dec) != tt.in {
+			t.Errorf("Decode(%q) = %x, %v, want %x", tt.want, dec, err, tt.in)
+		}
+	}
+}
+
+// sparseSrc returns n bytes of test data with zero runs of varied length.
+func sparseSrc(n int) []byte {
+	src := makeSrc(n)
+	for i, k := 0, 1; i < n; i, k = i+4*k+20, k*3%97 {
+		clear(src[i:min(n, i+4*k)])
+	}
+	return src
+}
+
+// TestR85ZRoundtrip verifies that r85z round-trips sparse data of many
+// lengths, and that the shortcuts only make the encoding shorter.
+func TestR85ZRoundtrip(t *testing.T) {
+	for _, n := range []int{0, 1, 4, 63, 64, 65, 128, 300, 1000, 5000} {
+		src := sparseSrc(n)
+		enc := R85ZEncoding.EncodeToString(src)
+		if len(enc) > len(EncodeToString(src)) {
+			t.Errorf("n=%d: r85z encoding is longer than r85", n)
+		}
+		dec, err := R85ZEncoding.DecodeString(enc)
+		if err != nil {
+			t.Fatalf("n=%d: Decode err = %v", n, err)
+		}
+		if !bytes.Equal(dec, src) {
+			t.Fatalf("n=%d: roundtrip mismatch", n)
+		}
+	}
+}
+
+// TestR85ZErrors verifies that misplaced markers are corrupt input, and
+// that StdEncoding rejects them.
+func TestR85ZErrors(t *testing.T) {
+	for _, s := range []string{"(!", "((((!(((((", "#", "(((((#", "##(", "#!", "(#("} {
+		if _, err := R85ZEncoding.DecodeString(s); err == nil {
+			t.Errorf("R85Z Decode(%q): expected error", s)
+		}
+	}
+	for _, s := range []string{"!", "#(", "((((!"} {
+		_, err := StdEncoding.DecodeString(s)
+		if _, ok := err.(CorruptInputError); !ok {
+			t.Errorf("Std Decode(%q): err = %v, want CorruptInputError", s, err)
+		}
+	}
+}
+
+// TestR85ZStreaming verifies that the streaming encoder and decoder
+// handle zero runs split across reads and writes: the encoder's text
+// matches Encode whatever the size of the writes.
+func TestR85ZStreaming(t *testing.T) {
+	for _, src := range [][]byte{sparseSrc(3000), make([]byte, 400), slices.Concat(make([]byte, 5000), []byte{1, 2})} {
+		text := R85ZEncoding.EncodeToString(src)
+		for _, size := range []int{1, 4, 7, 4096} {
+			var buf bytes.Buffer
+			w := R85ZEncoding.NewEncoder(&buf)
+			for i := 0; i < len(src); i += size {
+				if _, err := w.Write(src[i:min(len(src), i+size)]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != text {
+				t.Errorf("%d bytes in writes of %d: got %q, want %q", len(src), size, buf.String(), text)
+			}
+		}
+
+		got, err := io.ReadAll(R85ZEncoding.NewDecoder(iotest.OneByteReader(strings.NewReader(text))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, src) {
+			t.Fatal("streaming decode mismatch")
+		}
+	}
+}
+
+// TestR85ZMaxDecodedLen verifies that MaxDecodedLen bounds the decoding
+// of a text made only of zero runs.
+func TestR85ZMaxDecodedLen(t *testing.T) {
+	text := strings.Repeat("#|", 50)
+	dec, err := R85ZEncoding.DecodeString(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max := R85ZEncoding.MaxDecodedLen(len(text)); len(dec) > max {
+		t.Errorf("decoded %d bytes, MaxDecodedLen = %d", len(dec), max)
+	}
+}
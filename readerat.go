@@ -0,0 +1,386 @@
+package r85
+
+import (
+	"errors"
+	"io"
+)
+
+// errNotCanonical reports an encoding whose text cannot be read at
+// random offsets.
+var errNotCanonical = errors.New("r85: encoding has shortcuts or segments, so its text cannot be read at random offsets")
+
+// readerAtChunk is the number of blocks that a DecodedReaderAt decodes at a
+// time.
+const readerAtChunk = 1024
+
+// A DecodedReaderAt reads the data encoded in canonical text, which
+// it reads at random offsets from an [io.ReaderAt].  It implements
+// [io.ReaderAt] and [io.ReadSeeker].
+type DecodedReaderAt struct {
+	enc   *Encoding
+	r     io.ReaderAt
+	base  int64 // text offset of the first digit
+	chars int64 // number of digits
+	size  int64 // length of the data
+	off   int64 // offset of the next Read
+}
+
+// NewReaderAt returns a DecodedReaderAt for the r85 text of size bytes in r:
+// see [Encoding.NewReaderAt].
+func NewReaderAt(r io.ReaderAt, size int64) (*DecodedReaderAt, error) {
+	return StdEncoding.NewReaderAt(r, size)
+}
+
+// NewReaderAt returns a DecodedReaderAt for the canonical text of size
+// bytes in r, as written by enc.Encode: every 5 digits decode to 4 bytes,
+// so only the blocks that cover a read are read and decoded.  If enc has
+// grouping, the text must have the separator after every group, so text
+// wrapped at a fixed width can be read with an encoding such as
+// StdEncoding.WithGroups(76, "\n").  Framing must be present, and
+// skipped characters such as a final newline may follow the text, but
+// not precede it or appear within it.  NewReaderAt reads the whole text
+// once to check this, and reports a [CorruptInputError] otherwise.
+// Encodings with shortcuts or segments are not supported.
+func (enc *Encoding) NewReaderAt(r io.ReaderAt, size int64) (*DecodedReaderAt, error) {
+	if enc.zero != 0 || enc.zeroRun != 0 || enc.spaces != 0 || enc.segment != 0 {
+		return nil, errNotCanonical
+	}
+	d := &DecodedReaderAt{enc: enc, r: r, base: int64(len(enc.prefix))}
+
+	// Trim trailing skipped characters, then check the framing.
+	var tail [64]byte
+	end := size
+	for end > 0 {
+		n := min(end, int64(len(tail)))
+		if err := readFullAt(r, tail[:n], end-n); err != nil {
+			return nil, err
+		}
+		i := n
+		for i > 0 && enc.decodeMap[tail[i-1]] == digitSkip {
+			i--
+		}
+		end -= n - i
+		if i > 0 {
+			break
+		}
+	}
+	if end < d.base+int64(len(enc.suffix)) {
+		return nil, CorruptInputError{Reason: "missing end delimiter"}
+	}
+	for _, fr := range []struct {
+		s   string
+		off int64
+	}{{enc.prefix, 0}, {enc.suffix, end - int64(len(enc.suffix))}} {
+		b := make([]byte, len(fr.s))
+		if err := readFullAt(r, b, fr.off); err != nil {
+			return nil, err
+		}
+		if string(b) != fr.s {
+			return nil, CorruptInputError{Reason: "missing framing delimiter", Offset: int(fr.off)}
+		}
+	}
+	end -= int64(len(enc.suffix))
+
+	// Count the digits between the group separators.
+	n := end - d.base
+	if g, ls := int64(enc.groups), int64(len(enc.sep)); g > 0 && ls > 0 {
+		q, rem := n/(g+ls), n%(g+ls)
+		if rem > g || rem == 0 && q > 0 {
+			return nil, CorruptInputError{Reason: "text is not grouped canonically", Offset: int(end)}
+		}
+		n = q*g + rem
+	}
+	d.chars = n
+	if err := d.check(end); err != nil {
+		return nil, err
+	}
+
+	// Decode the final block to find the length of the data.
+	if n > 0 {
+		last := (n - 1) / 5
+		data, err := d.decode(last, last+1)
+		if err != nil {
+			return nil, err
+		}
+		d.size = 4*last + int64(len(data))
+	}
+	return d, nil
+}
+
+// check reads the text up to end, and reports any byte that is neither a
+// digit nor part of a group separator: a skipped character inside the
+// text would shift every block after it.
+func (d *DecodedReaderAt) check(end int64) error {
+	g, ls := int64(d.enc.groups), int64(len(d.enc.sep))
+	if g == 0 || ls == 0 {
+		g, ls = max(d.chars, 1), 0
+	}
+	var buf [4096]byte
+	for off := d.base; off < end; {
+		n := min(end-off, int64(len(buf)))
+		if err := readFullAt(d.r, buf[:n], off); err != nil {
+			return err
+		}
+		for i, c := range buf[:n] {
+			t := off + int64(i)
+			r := (t - d.base) % (g + ls)
+			if r < g && d.enc.decodeMap[c] >= 85 || r >= g && c != d.enc.sep[r-g] {
+				return CorruptInputError{Reason: "text is not canonical", Offset: int(t)}
+			}
+		}
+		off += n
+	}
+	return nil
+}
+
+// textOffset returns the offset in the text of digit c.
+func (d *DecodedReaderAt) textOffset(c int64) int64 {
+	if g := int64(d.enc.groups); g > 0 {
+		return d.base + c + c/g*int64(len(d.enc.sep))
+	}
+	return d.base + c
+}
+
+// decode reads and decodes blocks b0 up to b1, and returns the data.
+// Its buffers are its own, so that calls to ReadAt may run in parallel.
+func (d *DecodedReaderAt) decode(b0, b1 int64) ([]byte, error) {
+	c0, c1 := 5*b0, min(5*b1, d.chars)
+	t0, t1 := d.textOffset(c0), d.textOffset(c1-1)+1
+	text, data := make([]byte, t1-t0), make([]byte, 4*(b1-b0))
+	if err := readFullAt(d.r, text, t0); err != nil {
+		return nil, err
+	}
+	n, _, err := d.enc.decodeBlocks(data, text)
+	return data[:n], shiftOffset(err, int(t0))
+}
+
+// readFullAt reads len(b) bytes from r at offset off, and reports a short
+// read as io.ErrUnexpectedEOF.
+func readFullAt(r io.ReaderAt, b []byte, off int64) error {
+	n, err := r.ReadAt(b, off)
+	if n == len(b) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Size returns the length of the data.
+func (d *DecodedReaderAt) Size() int64 {
+	return d.size
+}
+
+// ReadAt reads len(p) bytes of the data starting at offset off.
+func (d *DecodedReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("r85: negative offset")
+	}
+	for len(p) > 0 && off < d.size {
+		b0 := off / 4
+		b1 := min((off+int64(len(p))+3)/4, b0+readerAtChunk, (d.chars+4)/5)
+		data, err := d.decode(b0, b1)
+		if err != nil {
+			return n, err
+		}
+		if int64(len(data)) != min(4*b1, d.size)-4*b0 {
+			// The text has changed since NewReaderAt checked it.
+			return n, CorruptInputError{Reason: "text is not canonical", Offset: int(d.textOffset(5 * b0))}
+		}
+		k := copy(p, data[off-4*b0:])
+		n += k
+		p = p[k:]
+		off += int64(k)
+	}
+	if len(p) > 0 {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read reads the data from the current offset.
+func (d *DecodedReaderAt) Read(p []byte) (int, error) {
+	n, err := d.ReadAt(p, d.off)
+	d.off += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek sets the offset of the next Read, as [io.Seeker] describes.
+func (d *DecodedReaderAt) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += d.off
+	case io.SeekEnd:
+		offset += d.size
+	case io.SeekStart:
+	default:
+		return 0, errors.New("r85: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("r85: negative position")
+	}
+	d.off = offset
+	return offset, nil
+}
+
+// An EncodedReaderAt reads the text that [Encoding.Encode] would write
+// for data that it reads at random offsets from an [io.ReaderAt].  It
+// implements [io.ReaderAt] and [io.ReadSeeker], so it can be passed to
+// http.ServeContent to serve ranges of the text of a large file.
+type EncodedReaderAt struct {
+	enc   *Encoding
+	r     io.ReaderAt
+	n     int64 // length of the data
+	chars int64 // number of digits
+	body  int64 // length of the digits and group separators
+	size  int64 // length of the text
+	off   int64 // offset of the next Read
+}
+
+// NewEncodedReaderAt returns an EncodedReaderAt for the data of size
+// bytes in r: see [Encoding.NewEncodedReaderAt].
+func NewEncodedReaderAt(r io.ReaderAt, size int64) (*EncodedReaderAt, error) {
+	return StdEncoding.NewEncodedReaderAt(r, size)
+}
+
+// NewEncodedReaderAt returns an EncodedReaderAt for the text of the data
+// of size bytes in r, which is enc.MaxEncodedLen(size) bytes long,
+// including any framing and group separators.  Only the blocks that
+// cover a read are read and encoded.  Encodings with shortcuts are not
+// supported, since the length of their text depends on the data.
+func (enc *Encoding) NewEncodedReaderAt(r io.ReaderAt, size int64) (*EncodedReaderAt, error) {
+	if enc.zero != 0 || enc.zeroRun != 0 || enc.spaces != 0 {
+		return nil, errNotCanonical
+	}
+	if size < 0 {
+		return nil, errors.New("r85: negative size")
+	}
+	if enc.partial == PartialNone && size%4 != 0 {
+		return nil, ErrPartialBlock
+	}
+	e := &EncodedReaderAt{enc: enc, r: r, n: size}
+	e.chars = 5 * (size / 4)
+	if rem := size % 4; rem != 0 {
+		if enc.partial == PartialZeroPad || enc.partial == PartialPadded {
+			e.chars += 5
+		} else {
+			e.chars += rem + 1
+		}
+	}
+	e.body = e.chars
+	if g, ls := int64(enc.groups), int64(len(enc.sep)); g > 0 && e.chars > 0 {
+		e.body += (e.chars - 1) / g * ls
+	}
+	e.size = int64(len(enc.prefix)) + e.body + int64(len(enc.suffix))
+	return e, nil
+}
+
+// Size returns the length of the text.
+func (e *EncodedReaderAt) Size() int64 {
+	return e.size
+}
+
+// ReadAt reads len(p) bytes of the text starting at offset off.
+func (e *EncodedReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("r85: negative offset")
+	}
+	prefix := int64(len(e.enc.prefix))
+	for len(p) > 0 && off < e.size {
+		var k int
+		switch {
+		case off < prefix:
+			k = copy(p, e.enc.prefix[off:])
+		case off >= prefix+e.body:
+			k = copy(p, e.enc.suffix[off-prefix-e.body:])
+		default:
+			k, err = e.readBody(p[:min(int64(len(p)), prefix+e.body-off)], off-prefix)
+			if err != nil {
+				return n, err
+			}
+		}
+		n += k
+		p = p[k:]
+		off += int64(k)
+	}
+	if len(p) > 0 {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readBody fills part of p with the digits and separators that start at
+// offset off of the body of the text, and returns the number of bytes.
+func (e *EncodedReaderAt) readBody(p []byte, off int64) (int, error) {
+	g, ls := int64(e.enc.groups), int64(len(e.enc.sep))
+	if g == 0 || ls == 0 {
+		g, ls = e.chars, 0
+	}
+	// charAt returns the digit at or after body offset t.
+	charAt := func(t int64) int64 {
+		return t/(g+ls)*g + min(t%(g+ls), g)
+	}
+	end := off + int64(len(p))
+	c0, c1 := charAt(off), charAt(end-1)+1
+	b0 := c0 / 5
+	b1 := min((c1+4)/5, b0+readerAtChunk)
+	d0, d1 := 4*b0, min(4*b1, e.n)
+	// The buffers are this call's own, so that calls to ReadAt may run
+	// in parallel.
+	data, text := make([]byte, d1-d0), make([]byte, 5*(b1-b0))
+	if err := readFullAt(e.r, data, d0); err != nil {
+		return 0, err
+	}
+	text = text[:e.enc.encodeBlocks(text, data)]
+
+	// Copy the digits, and the separators between them.
+	n := 0
+	for t := off; t < end; {
+		q, r := t/(g+ls), t%(g+ls)
+		var k int
+		if r < g {
+			c := q*g + r - 5*b0
+			if c >= int64(len(text)) {
+				break
+			}
+			k = copy(p[n:], text[c:min(c+g-r, int64(len(text)))])
+		} else {
+			k = copy(p[n:], e.enc.sep[r-g:])
+		}
+		n += k
+		t += int64(k)
+	}
+	return n, nil
+}
+
+// Read reads the text from the current offset.
+func (e *EncodedReaderAt) Read(p []byte) (int, error) {
+	n, err := e.ReadAt(p, e.off)
+	e.off += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek sets the offset of the next Read, as [io.Seeker] describes.
+func (e *EncodedReaderAt) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += e.off
+	case io.SeekEnd:
+		offset += e.size
+	case io.SeekStart:
+	default:
+		return 0, errors.New("r85: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("r85: negative position")
+	}
+	e.off = offset
+	return offset, nil
+}
@@ -0,0 +1,255 @@
+package r85
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+// TestDecodedReaderAt verifies reads at various offsets and lengths
+// against the data, for plain, framed and wrapped text.
+func TestDecodedReaderAt(t *testing.T) {
+	encs := []struct {
+		name    string
+		enc     *Encoding
+		trailer string
+	}{
+		{"std", StdEncoding, ""},
+		{"padded", PaddedEncoding, "\n"},
+		{"adobe", Ascii85Encoding.WithZero(0).WithFraming("<~", "~>"), "\r\n"},
+		{"wrapped", StdEncoding.WithGroups(76, "\n"), "\n"},
+		{"crlf", RFC1924Encoding.WithGroups(10, "\r\n"), ""},
+	}
+	for _, te := range encs {
+		for _, n := range []int{0, 1, 2, 3, 4, 5, 303, 5000} {
+			src := makeSrc(n)
+			text := te.enc.EncodeToString(src) + te.trailer
+			r, err := te.enc.NewReaderAt(strings.NewReader(text), int64(len(text)))
+			if err != nil {
+				t.Fatalf("%s: NewReaderAt(%d bytes): %v", te.name, n, err)
+			}
+			if r.Size() != int64(n) {
+				t.Errorf("%s: Size() = %d, want %d", te.name, r.Size(), n)
+			}
+			for _, off := range []int{0, 1, n / 3, n - 5, n - 1} {
+				off = max(off, 0)
+				for _, l := range []int{1, 4, 7, 4100} {
+					p := make([]byte, l)
+					m, err := r.ReadAt(p, int64(off))
+					want := src[min(off, n):min(off+l, n)]
+					if !bytes.Equal(p[:m], want) || (m < l) != (err == io.EOF) || err != nil && err != io.EOF {
+						t.Errorf("%s: ReadAt(%d bytes at %d of %d) = %d, %v", te.name, l, off, n, m, err)
+					}
+				}
+			}
+			if err := iotest.TestReader(r, src); err != nil {
+				t.Errorf("%s: %d bytes: %v", te.name, n, err)
+			}
+		}
+	}
+}
+
+// TestDecodedReaderAtErrors verifies that non-canonical and damaged
+// text is reported.
+func TestDecodedReaderAtErrors(t *testing.T) {
+	for _, text := range []string{"((((((", "<~(((((", "((((((((((\n(("} {
+		enc := StdEncoding
+		if text[0] == '<' {
+			enc = Ascii85Encoding.WithZero(0).WithFraming("<~", "~>")
+		} else if strings.Contains(text, "\n") {
+			enc = StdEncoding.WithGroups(5, "\n")
+		}
+		if _, err := enc.NewReaderAt(strings.NewReader(text), int64(len(text))); err == nil {
+			t.Errorf("NewReaderAt(%q) did not fail", text)
+		}
+	}
+	// Skipped characters before or within the digits would misalign the
+	// blocks, and once made ReadAt panic or loop forever.
+	for _, text := range []string{"(((((  ((((((((((", "     ((", "(((((\n((((("} {
+		_, err := NewReaderAt(strings.NewReader(text), int64(len(text)))
+		if _, ok := err.(CorruptInputError); !ok {
+			t.Errorf("NewReaderAt(%q): err = %v, want CorruptInputError", text, err)
+		}
+	}
+	if _, err := R85ZEncoding.NewReaderAt(strings.NewReader(""), 0); err == nil {
+		t.Errorf("NewReaderAt with shortcuts did not fail")
+	}
+
+	// Damage is reported with its offset in the text.
+	text := EncodeToString(makeSrc(40))
+	text = text[:20] + "|||||" + text[25:]
+	r, err := NewReaderAt(strings.NewReader(text), int64(len(text)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.ReadAt(make([]byte, 4), 16)
+	if ce, ok := err.(CorruptInputError); !ok || ce.Offset != 20 {
+		t.Errorf("ReadAt of a damaged block: err = %v, want offset 20", err)
+	}
+	if _, err = r.ReadAt(make([]byte, 4), 0); err != nil {
+		t.Errorf("ReadAt of an intact block: %v", err)
+	}
+}
+
+// TestDecodedReaderAtParallel verifies that parallel calls to ReadAt
+// each get their own data.
+func TestDecodedReaderAtParallel(t *testing.T) {
+	src := makeSrc(100000)
+	text := StdEncoding.WithGroups(76, "\n").EncodeToString(src)
+	r, err := StdEncoding.WithGroups(76, "\n").NewReaderAt(strings.NewReader(text), int64(len(text)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			p := make([]byte, 5001)
+			for off := g * 997; off+len(p) <= len(src); off += 8 * 997 {
+				if _, err := r.ReadAt(p, int64(off)); err != nil || !bytes.Equal(p, src[off:off+len(p)]) {
+					t.Errorf("ReadAt(%d bytes at %d) = %v, or wrong data", len(p), off, err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// truncatingReaderAt is an io.ReaderAt whose content can be cut short.
+type truncatingReaderAt struct {
+	b []byte
+}
+
+func (r *truncatingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return bytes.NewReader(r.b).ReadAt(p, off)
+}
+
+// TestDecodedReaderAtTruncated verifies that text shorter than its
+// stated size is reported, rather than decoding stale bytes.
+func TestDecodedReaderAtTruncated(t *testing.T) {
+	text := []byte(EncodeToString(makeSrc(1000)))
+	if _, err := NewReaderAt(bytes.NewReader(text[:100]), int64(len(text))); err != io.ErrUnexpectedEOF {
+		t.Errorf("NewReaderAt of short text: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+	tr := &truncatingReaderAt{text}
+	r, err := NewReaderAt(tr, int64(len(text)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ReadAt(make([]byte, 800), 0)
+	tr.b = text[:500]
+	if _, err := r.ReadAt(make([]byte, 800), 0); err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadAt of truncated text: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+
+	// Text that changes under the reader after it was checked is
+	// reported, not misread.
+	tr.b = slices.Concat(text[:6], []byte(" "), text[6:len(text)-1])
+	for _, off := range []int64{4, 7} {
+		if _, err := r.ReadAt(make([]byte, 1), off); err == nil {
+			t.Errorf("ReadAt(1 byte at %d) of changed text did not fail", off)
+		}
+	}
+}
+
+// TestEncodedReaderAt verifies reads at every offset against the text
+// from EncodeToString.
+func TestEncodedReaderAt(t *testing.T) {
+	encs := []struct {
+		name string
+		enc  *Encoding
+	}{
+		{"std", StdEncoding},
+		{"padded", PaddedEncoding},
+		{"zeropad", RFC1924PadEncoding},
+		{"adobe", Ascii85Encoding.WithZero(0).WithFraming("<~", "~>")},
+		{"wrapped", StdEncoding.WithGroups(76, "\n")},
+		{"crlf", SortableEncoding.WithGroups(7, "\r\n").WithFraming("'", "'")},
+	}
+	for _, te := range encs {
+		for _, n := range []int{0, 1, 2, 3, 4, 5, 303, 9000} {
+			src := makeSrc(n)
+			want := te.enc.EncodeToString(src)
+			r, err := te.enc.NewEncodedReaderAt(bytes.NewReader(src), int64(n))
+			if err != nil {
+				t.Fatalf("%s: NewEncodedReaderAt(%d bytes): %v", te.name, n, err)
+			}
+			if r.Size() != int64(len(want)) || r.Size() != int64(te.enc.MaxEncodedLen(n)) {
+				t.Errorf("%s: Size() = %d, want %d", te.name, r.Size(), len(want))
+			}
+			if err := iotest.TestReader(r, []byte(want)); err != nil {
+				t.Errorf("%s: %d bytes: %v", te.name, n, err)
+			}
+			if n > 400 {
+				continue
+			}
+			for off := range len(want) {
+				for l := 1; off+l <= len(want)+1; l += 3 {
+					p := make([]byte, l)
+					m, err := r.ReadAt(p, int64(off))
+					if string(p[:m]) != want[off:min(off+l, len(want))] || (m < l) != (err == io.EOF) {
+						t.Fatalf("%s: ReadAt(%d bytes at %d of %d) = %q, %v", te.name, l, off, len(want), p[:m], err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestEncodedReaderAtParallel verifies that parallel calls to ReadAt
+// each get their own text.
+func TestEncodedReaderAtParallel(t *testing.T) {
+	src := makeSrc(100000)
+	enc := StdEncoding.WithGroups(76, "\n")
+	text := enc.EncodeToString(src)
+	r, err := enc.NewEncodedReaderAt(bytes.NewReader(src), int64(len(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			p := make([]byte, 6001)
+			for off := g * 997; off+len(p) <= len(text); off += 8 * 997 {
+				if _, err := r.ReadAt(p, int64(off)); err != nil || string(p) != text[off:off+len(p)] {
+					t.Errorf("ReadAt(%d bytes at %d) = %v, or wrong text", len(p), off, err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// TestEncodedReaderAtTruncated verifies that data shorter than its
+// stated size is reported, rather than encoded as zeros.
+func TestEncodedReaderAtTruncated(t *testing.T) {
+	r, err := NewEncodedReaderAt(bytes.NewReader(makeSrc(100)), 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadAt(make([]byte, 50), 0); err != nil {
+		t.Errorf("ReadAt within the data: %v", err)
+	}
+	if _, err := r.ReadAt(make([]byte, r.Size()), 0); err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadAt past the data: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if _, err := io.ReadAll(r); err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadAll: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+// TestEncodedReaderAtErrors verifies that unsupported encodings and
+// lengths are rejected.
+func TestEncodedReaderAtErrors(t *testing.T) {
+	if _, err := Z85Encoding.NewEncodedReaderAt(bytes.NewReader(nil), 3); err == nil {
+		t.Errorf("Z85 with a partial block did not fail")
+	}
+	if _, err := AdobeEncoding.NewEncodedReaderAt(bytes.NewReader(nil), 0); err == nil {
+		t.Errorf("encoding with shortcuts did not fail")
+	}
+}
@@ -0,0 +1,13 @@
+package r85
+
+// rfc1924Alphabet is the alphabet of RFC 1924, also used by Python's
+// base64.b85encode, Mercurial and git binary patches.
+const rfc1924Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~"
+
+// RFC1924Encoding uses the RFC 1924 alphabet with [PartialTruncate]
+// partial blocks, matching Python's base64.b85encode and b85decode.
+var RFC1924Encoding = NewEncoding(rfc1924Alphabet).WithPartial(PartialTruncate)
+
+// RFC1924PadEncoding uses the RFC 1924 alphabet with [PartialZeroPad]
+// partial blocks, matching Python's base64.b85encode with pad=True.
+var RFC1924PadEncoding = NewEncoding(rfc1924Alphabet).WithPartial(PartialZeroPad)
@@ -0,0 +1,103 @@
+package r85
+
+import (
+	"bytes"
+	"testing"
+)
+
+// b85Tests are copied from CPython's Lib/test/test_base64.py
+// (test_b85encode and test_b85decode).
+var b85Tests = []struct {
+	data, text string
+}{
+	{"", ""},
+	{"www.python.org", "cXxL#aCvlSZ*DGca%T"},
+	{string(b85Range255()), "009C61O)~M2nh-c3=Iws5D^j+6crX17#SKH9337X" +
+		"AR!_nBqb&%C@Cr{EG;fCFflSSG&MFiI5|2yJUu=?KtV!7L`6nNNJ&ad" +
+		"OifNtP*GA-R8>}2SXo+ITwPvYU}0ioWMyV&XlZI|Y;A6DaB*^Tbai%j" +
+		"czJqze0_d@fPsR8goTEOh>41ejE#<ukdcy;l$Dm3n3<ZJoSmMZprN9p" +
+		"q@|{(sHv)}tgWuEu(7hUw6(UkxVgH!yuH4^z`?@9#Kp$P$jQpf%+1cv" +
+		"(9zP<)YaD4*xB0K+}+;a;Njxq<mKk)=;`X~?CtLF@bU8V^!4`l`1$(#" +
+		"{Qdp"},
+	{"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ" +
+		"0123456789!@#0^&*();:<>,. []{}",
+		"VPa!sWoBn+X=-b1ZEkOHadLBXb#`}nd3r%YLqtVJM@UIZOH55pPf$@(" +
+			"Q&d$}S6EqEFflSSG&MFiI5{CeBQRbjDkv#CIy^osE+AW7dwl"},
+	{"no padding..", "Zf_uPVPs@!Zf7no"},
+	{"zero compression\x00\x00\x00\x00", "dS!BNAY*TBaB^jHb7^mG00000"},
+	{"zero compression\x00\x00\x00", "dS!BNAY*TBaB^jHb7^mG0000"},
+	{"Boundary:\x00\x00\x00\x00", "LT`0$WMOi7IsgCw00"},
+	{"Space compr:    ", "Q*dEpWgug3ZE$irARr(h"},
+	{"\xff", "{{"},
+	{"\xff\xff", "|Nj"},
+	{"\xff\xff\xff", "|Ns9"},
+	{"\xff\xff\xff\xff", "|NsC0"},
+}
+
+func b85Range255() []byte {
+	b := make([]byte, 255)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
+
+// TestRFC1924Python tests RFC1924Encoding against CPython's vectors.
+func TestRFC1924Python(t *testing.T) {
+	for _, tt := range b85Tests {
+		if got := RFC1924Encoding.EncodeToString([]byte(tt.data)); got != tt.text {
+			t.Errorf("Encode(%q) = %q, want %q", tt.data, got, tt.text)
+		}
+		got, err := RFC1924Encoding.DecodeString(tt.text)
+		if err != nil || string(got) != tt.data {
+			t.Errorf("Decode(%q) = %q, %v, want %q", tt.text, got, err, tt.data)
+		}
+	}
+}
+
+// TestRFC1924PythonPad tests RFC1924PadEncoding against CPython's
+// test_b85_padding vectors.
+func TestRFC1924PythonPad(t *testing.T) {
+	tests := []struct {
+		data, text, decoded string
+	}{
+		{"x", "cmMzZ", "x\x00\x00\x00"},
+		{"xx", "cz6H+", "xx\x00\x00"},
+		{"xxx", "czAdK", "xxx\x00"},
+		{"xxxx", "czAet", "xxxx"},
+		{"xxxxx", "czAetcmMzZ", "xxxxx\x00\x00\x00"},
+	}
+	for _, tt := range tests {
+		if got := RFC1924PadEncoding.EncodeToString([]byte(tt.data)); got != tt.text {
+			t.Errorf("Encode(%q) = %q, want %q", tt.data, got, tt.text)
+		}
+		got, err := RFC1924PadEncoding.DecodeString(tt.text)
+		if err != nil || string(got) != tt.decoded {
+			t.Errorf("Decode(%q) = %q, %v, want %q", tt.text, got, err, tt.decoded)
+		}
+	}
+}
+
+// TestRFC1924PythonErrors tests CPython's test_b85decode_errors overflow cases.
+func TestRFC1924PythonErrors(t *testing.T) {
+	for _, s := range []string{"|", "|N", "|Ns", "|NsC", "|NsC1"} {
+		if _, err := RFC1924Encoding.DecodeString(s); err == nil {
+			t.Errorf("Decode(%q): expected error, got nil", s)
+		}
+	}
+}
+
+// TestRFC1924Large exercises the remapped SIMD path.
+func TestRFC1924Large(t *testing.T) {
+	src := makeSrc(1001)
+	for _, enc := range []*Encoding{RFC1924Encoding, RFC1924PadEncoding} {
+		text := enc.EncodeToString(src)
+		if len(text) != enc.MaxEncodedLen(len(src)) {
+			t.Errorf("encoded length = %d, want %d", len(text), enc.MaxEncodedLen(len(src)))
+		}
+		got, err := enc.DecodeString(text)
+		if err != nil || !bytes.Equal(got[:len(src)], src) {
+			t.Errorf("roundtrip: err = %v", err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package r85
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestSegments flushes after every write, and checks that the text so
+// far decodes to everything written, for several segmented encodings.
+func TestSegments(t *testing.T) {
+	for _, enc := range []*Encoding{SegmentedEncoding, PaddedEncoding.WithSegments('%'), AdobeEncoding.WithSegments('|')} {
+		src := makeSrc(100)
+		var text bytes.Buffer
+		w := enc.NewEncoder(&text).(Flusher)
+		for i, n := 0, 1; i < len(src); i, n = i+n, n%7+1 {
+			chunk := src[i:min(len(src), i+n)]
+			w.Write(chunk)
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+			// Everything written so far can be decoded.
+			got := make([]byte, 200)
+			m, _, err := enc.Decode(got, []byte(text.String()+enc.suffix))
+			if err != nil || !bytes.Equal(got[:m], src[:i+len(chunk)]) {
+				t.Fatalf("after Flush at %d: decoded %d bytes, %v", i+len(chunk), m, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+
+		got, err := io.ReadAll(enc.NewDecoder(iotest.OneByteReader(bytes.NewReader(text.Bytes()))))
+		if err != nil || !bytes.Equal(got, src) {
+			t.Errorf("NewDecoder: got %d bytes, %v", len(got), err)
+		}
+	}
+}
+
+// TestSegmentsFlushEmpty checks that Flush ends only segments that have
+// input.
+func TestSegmentsFlushEmpty(t *testing.T) {
+	var text strings.Builder
+	w := SegmentedEncoding.NewEncoder(&text).(Flusher)
+	w.Flush()
+	w.Write([]byte("abcd"))
+	w.Flush()
+	w.Flush()
+	w.Write([]byte("e"))
+	w.Flush()
+	w.Close()
+	if got, want := text.String(), EncodeToString([]byte("abcd"))+"!"+EncodeToString([]byte("e"))+"!"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestSegmentsErrors checks a single character before a delimiter, and
+// that StdEncoding rejects the delimiter rather than misreading the
+// short block before it.
+func TestSegmentsErrors(t *testing.T) {
+	dst := make([]byte, 16)
+	_, _, err := Decode(dst, []byte("((!(("))
+	if _, ok := err.(CorruptInputError); !ok {
+		t.Errorf("StdEncoding: err = %v, want CorruptInputError", err)
+	}
+	_, _, err = SegmentedEncoding.Decode(dst, []byte("(((((( !(("))
+	if ce, ok := err.(CorruptInputError); !ok || ce.Offset != 5 {
+		t.Errorf("single character before delimiter: err = %v, want offset 5", err)
+	}
+}
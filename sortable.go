@@ -0,0 +1,12 @@
+package r85
+
+// sortableAlphabet holds the characters of the r85 alphabet in ASCII
+// order, so that digit order matches byte order.
+const sortableAlphabet = "()*+,-./0123456789:;=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_abcdefghijklmnopqrstuvwxyz{|}~"
+
+// SortableEncoding uses the same characters as r85, but assigns digit
+// values in ASCII order: '(' is 0 and '~' is 84.  Encodings of inputs of
+// the same length therefore compare, with bytes.Compare or a plain
+// string comparison, in the same order as the inputs.  This does not
+// hold between inputs of different lengths.
+var SortableEncoding = NewEncoding(sortableAlphabet)
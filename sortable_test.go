@@ -0,0 +1,54 @@
+package r85
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// TestSortableAlphabet verifies that SortableEncoding uses the r85
+// characters in ASCII order.
+func TestSortableAlphabet(t *testing.T) {
+	r85 := []byte(string(encTable[:]))
+	slices.Sort(r85)
+	if string(r85) != sortableAlphabet {
+		t.Errorf("sortable alphabet = %q, want %q", sortableAlphabet, r85)
+	}
+}
+
+// TestSortableOrder verifies that encoded order matches binary order for
+// fixed-length inputs.
+func TestSortableOrder(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{1, 2, 3, 4, 7, 8, 16} {
+		a := make([]byte, n)
+		b := make([]byte, n)
+		for range 2000 {
+			for i := range a {
+				a[i] = byte(rng.Uint32())
+				b[i] = byte(rng.Uint32())
+			}
+			// Make shared prefixes and small differences common.
+			copy(b, a[:rng.IntN(n+1)])
+			want := bytes.Compare(a, b)
+			got := bytes.Compare([]byte(SortableEncoding.EncodeToString(a)), []byte(SortableEncoding.EncodeToString(b)))
+			if got != want {
+				t.Fatalf("order of %x, %x: got %d, want %d", a, b, got, want)
+			}
+		}
+	}
+
+	// Consecutive counters stay in order across the SIMD path.
+	var prev string
+	buf := make([]byte, 64)
+	for i := range uint64(1000) {
+		binary.BigEndian.PutUint64(buf[56:], i*0x0123456789)
+		s := SortableEncoding.EncodeToString(buf)
+		if s <= prev {
+			t.Fatalf("encoding of %d sorts before its predecessor", i)
+		}
+		prev = s
+	}
+}
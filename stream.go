@@ -0,0 +1,146 @@
+package r85
+
+import (
+	"bytes"
+	"io"
+)
+
+// NewEncodingReader returns a reader of the r85 text of r: see
+// [Encoding.NewEncodingReader].
+func NewEncodingReader(r io.Reader) io.Reader {
+	return StdEncoding.NewEncodingReader(r)
+}
+
+// NewDecodingWriter returns a writer that decodes r85 text to w: see
+// [Encoding.NewDecodingWriter].
+func NewDecodingWriter(w io.Writer, opts ...DecoderOption) io.WriteCloser {
+	return StdEncoding.NewDecodingWriter(w, opts...)
+}
+
+// NewEncodingReader returns a reader that yields the text that
+// enc.NewEncoder would write for the data of r, ending with the final
+// block and any suffix once r reports io.EOF.  It is the pull-style
+// counterpart of NewEncoder, for APIs that take an io.Reader, such as an
+// HTTP request body.
+func (enc *Encoding) NewEncodingReader(r io.Reader) io.Reader {
+	er := &encodingReader{r: r}
+	er.enc = enc.NewEncoder(&er.buf)
+	return er
+}
+
+type encodingReader struct {
+	r   io.Reader
+	enc io.WriteCloser
+	buf bytes.Buffer // encoded text not yet read
+	in  [3072]byte
+	err error
+}
+
+func (er *encodingReader) Read(p []byte) (int, error) {
+	for er.buf.Len() == 0 && er.err == nil {
+		n, err := er.r.Read(er.in[:])
+		if _, werr := er.enc.Write(er.in[:n]); werr != nil {
+			er.err = werr
+		} else if err == io.EOF {
+			er.err = io.EOF
+			if cerr := er.enc.Close(); cerr != nil {
+				er.err = cerr
+			}
+		} else if err != nil {
+			er.err = err
+		}
+	}
+	if er.buf.Len() > 0 {
+		return er.buf.Read(p)
+	}
+	return 0, er.err
+}
+
+// NewDecodingWriter returns a writer that decodes the text written to
+// it, as enc.NewDecoder would, and writes the data to w.  It is the
+// push-style counterpart of NewDecoder.  Incomplete blocks are carried
+// between writes, and Close decodes the final block, reporting a
+// CorruptInputError if it is a single dangling character.  Close does
+// not close w.
+func (enc *Encoding) NewDecodingWriter(w io.Writer, opts ...DecoderOption) io.WriteCloser {
+	dw := &decodingWriter{w: w}
+	dw.dec = enc.NewDecoder(&dw.src, opts...)
+	return dw
+}
+
+type decodingWriter struct {
+	w    io.Writer
+	src  pendingReader
+	dec  io.Reader
+	out  [4096]byte
+	done bool // whether the decoder has reached the end of the text
+	err  error
+}
+
+func (dw *decodingWriter) Write(p []byte) (int, error) {
+	if dw.err != nil {
+		return 0, dw.err
+	}
+	if dw.done {
+		// Like Decode, ignore anything after the end delimiter.
+		return len(p), nil
+	}
+	dw.src.p = p
+	dw.err = dw.drain()
+	n := len(p) - len(dw.src.p)
+	dw.src.p = nil
+	if dw.err != nil {
+		return n, dw.err
+	}
+	return len(p), nil
+}
+
+func (dw *decodingWriter) Close() error {
+	if dw.err != nil || dw.done {
+		return dw.err
+	}
+	dw.src.closed = true
+	dw.err = dw.drain()
+	return dw.err
+}
+
+// drain decodes the pending input and writes the data to w.
+func (dw *decodingWriter) drain() error {
+	for {
+		n, err := dw.dec.Read(dw.out[:])
+		if n > 0 {
+			if _, werr := dw.w.Write(dw.out[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			dw.done = true
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if n == 0 && len(dw.src.p) == 0 {
+			return nil
+		}
+	}
+}
+
+// pendingReader gives a decoder the text passed to one Write.  It
+// reports that nothing is available once that has been read, and
+// io.EOF once the writer is closed.
+type pendingReader struct {
+	p      []byte
+	closed bool
+}
+
+func (r *pendingReader) Read(b []byte) (int, error) {
+	if len(r.p) == 0 {
+		if r.closed {
+			return 0, io.EOF
+		}
+		return 0, nil
+	}
+	n := copy(b, r.p)
+	r.p = r.p[n:]
+	return n, nil
+}
@@ -0,0 +1,89 @@
+package r85
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var streamEncodings = []struct {
+	name string
+	enc  *Encoding
+}{
+	{"std", StdEncoding},
+	{"padded", PaddedEncoding},
+	{"r85z", R85ZEncoding},
+	{"adobe", AdobeEncoding},
+	{"grouped", StdEncoding.WithGroups(10, "\n")},
+}
+
+// TestEncodingReader checks that the encoding reader matches
+// EncodeToString through short reads on both sides.
+func TestEncodingReader(t *testing.T) {
+	for _, se := range streamEncodings {
+		for _, n := range []int{0, 1, 5, 4096, 10001} {
+			src := makeSrc(n)
+			copy(src[n/2:], make([]byte, min(n/2, 40)))
+			want := se.enc.EncodeToString(src)
+			got, err := io.ReadAll(iotest.OneByteReader(se.enc.NewEncodingReader(iotest.HalfReader(bytes.NewReader(src)))))
+			if err != nil || string(got) != want {
+				t.Errorf("%s: NewEncodingReader(%d bytes) = %d bytes, %v; want %d bytes",
+					se.name, n, len(got), err, len(want))
+			}
+		}
+	}
+}
+
+// TestDecodingWriter checks that text written in chunks of any size
+// decodes to the original data.
+func TestDecodingWriter(t *testing.T) {
+	for _, se := range streamEncodings {
+		for _, n := range []int{0, 1, 5, 4096, 10001} {
+			src := makeSrc(n)
+			text := se.enc.EncodeToString(src)
+			for _, chunk := range []int{1, 3, 1000} {
+				var buf bytes.Buffer
+				w := se.enc.NewDecodingWriter(&buf)
+				for i := 0; i < len(text); i += chunk {
+					if _, err := w.Write([]byte(text[i:min(len(text), i+chunk)])); err != nil {
+						t.Fatalf("%s: Write: %v", se.name, err)
+					}
+				}
+				if err := w.Close(); err != nil || !bytes.Equal(buf.Bytes(), src) {
+					t.Errorf("%s: decoded %d bytes in chunks of %d: got %d bytes, %v",
+						se.name, n, chunk, buf.Len(), err)
+				}
+			}
+		}
+	}
+}
+
+// TestDecodingWriterErrors checks the errors of a dangling character
+// and a missing suffix, and that text after the suffix is ignored.
+func TestDecodingWriterErrors(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewDecodingWriter(&buf)
+	io.WriteString(w, "(((((")
+	io.WriteString(w, "(")
+	if _, ok := w.Close().(CorruptInputError); !ok || buf.Len() != 4 {
+		t.Errorf("dangling character: Close did not report a CorruptInputError")
+	}
+
+	buf.Reset()
+	w = AdobeEncoding.NewDecodingWriter(&buf)
+	io.WriteString(w, "<~z~")
+	if _, err := io.WriteString(w, "> junk"); err != nil {
+		t.Errorf("Write after suffix: %v", err)
+	}
+	if err := w.Close(); err != nil || buf.Len() != 4 {
+		t.Errorf("Close = %v with %d bytes, want nil with 4", err, buf.Len())
+	}
+
+	w = AdobeEncoding.NewDecodingWriter(io.Discard)
+	io.WriteString(w, strings.Repeat("z", 10))
+	if err := w.Close(); err == nil {
+		t.Errorf("missing suffix: Close did not report an error")
+	}
+}
@@ -0,0 +1,95 @@
+package r85
+
+import (
+	"bufio"
+	"iter"
+)
+
+// TokenOptions selects which runs of digits [Encoding.SplitTokens] and
+// [Encoding.Tokens] report.  A token is a maximal run of digits of the
+// alphabet, including its aliases, whose length is a legal encoded
+// length: never 1 more than a multiple of 5.  Runs shorter than MinLen
+// or, if MaxLen is positive, longer than MaxLen are passed over whole,
+// never cut down.  Setting both to the same value matches an exact
+// length, such as 15 for a 96-bit ID or 20 for a UUID.
+type TokenOptions struct {
+	MinLen int
+	MaxLen int
+}
+
+// match reports whether a run of n digits is a token.
+func (o TokenOptions) match(n int) bool {
+	return n > 0 && n%5 != 1 && n >= o.MinLen && (o.MaxLen <= 0 || n <= o.MaxLen)
+}
+
+// ScanTokens is a [bufio.SplitFunc] that returns each r85 token in the
+// input, with no limits on its length: see [Encoding.SplitTokens].
+func ScanTokens(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	return StdEncoding.SplitTokens(TokenOptions{})(data, atEOF)
+}
+
+// Tokens returns an iterator over the r85 tokens in text and their
+// offsets: see [Encoding.Tokens].
+func Tokens(text []byte, opts TokenOptions) iter.Seq2[[]byte, int] {
+	return StdEncoding.Tokens(text, opts)
+}
+
+// SplitTokens returns a [bufio.SplitFunc] that finds the tokens selected
+// by opts in mixed text, such as log lines, and returns each one without
+// the text around it.  The returned function tracks runs that are too
+// long for the Scanner's buffer, so it must not be shared between
+// Scanners.
+func (enc *Encoding) SplitTokens(opts TokenOptions) bufio.SplitFunc {
+	skipping := false // in the rest of a run already passed over
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		i := 0
+		for {
+			for i < len(data) && enc.decodeMap[data[i]] >= 85 {
+				skipping = false
+				i++
+			}
+			j := i
+			for j < len(data) && enc.decodeMap[data[j]] < 85 {
+				j++
+			}
+			if j == len(data) && !atEOF {
+				if opts.MaxLen > 0 && j-i > opts.MaxLen {
+					// Too long already: pass over what has been read.
+					skipping = true
+					return j, nil, nil
+				}
+				// Wait for the end of the run.
+				return i, nil, nil
+			}
+			if i == j {
+				return j, nil, nil
+			}
+			if !skipping && opts.match(j-i) {
+				return j, data[i:j], nil
+			}
+			skipping = false
+			i = j
+		}
+	}
+}
+
+// Tokens returns an iterator over the tokens selected by opts in text,
+// yielding each with its offset in text.
+func (enc *Encoding) Tokens(text []byte, opts TokenOptions) iter.Seq2[[]byte, int] {
+	return func(yield func([]byte, int) bool) {
+		for i := 0; i < len(text); {
+			if enc.decodeMap[text[i]] >= 85 {
+				i++
+				continue
+			}
+			j := i + 1
+			for j < len(text) && enc.decodeMap[text[j]] < 85 {
+				j++
+			}
+			if opts.match(j-i) && !yield(text[i:j], i) {
+				return
+			}
+			i = j
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package r85
+
+import (
+	"bufio"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestTokens checks the tokens that Tokens and SplitTokens find with
+// and without length limits.
+func TestTokens(t *testing.T) {
+	id := EncodeToString(makeSrc(12))   // 15 characters
+	uuid := EncodeToString(makeSrc(16)) // 20 characters
+	text := "id " + id + " uuid=" + uuid + " x\t123456&" + strings.Repeat("a", 40) + " ok"
+	tests := []struct {
+		opts TokenOptions
+		want []string
+	}{
+		{TokenOptions{}, []string{"id", id, "uuid=" + uuid, strings.Repeat("a", 40), "ok"}},
+		{TokenOptions{MinLen: 15, MaxLen: 15}, []string{id}},
+		{TokenOptions{MinLen: 3, MaxLen: 25}, []string{id, "uuid=" + uuid}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for tok, off := range Tokens([]byte(text), tt.opts) {
+			if text[off:off+len(tok)] != string(tok) {
+				t.Errorf("%+v: token %q at wrong offset %d", tt.opts, tok, off)
+			}
+			got = append(got, string(tok))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Tokens(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+
+		// The SplitFunc finds the same tokens, even through a small
+		// buffer and one byte at a time.
+		s := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(text)))
+		s.Buffer(make([]byte, 0, 32), 32)
+		s.Split(StdEncoding.SplitTokens(tt.opts))
+		got = got[:0]
+		for s.Scan() {
+			got = append(got, s.Text())
+		}
+		if s.Err() != nil && tt.opts.MaxLen == 0 {
+			// Without a limit, a long run fills the buffer.
+			continue
+		}
+		if s.Err() != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("SplitTokens(%+v) = %q, %v; want %q", tt.opts, got, s.Err(), tt.want)
+		}
+	}
+}
+
+// TestScanTokens checks that ScanTokens passes over runs whose length
+// is not a legal encoded length.
+func TestScanTokens(t *testing.T) {
+	s := bufio.NewScanner(strings.NewReader("ab & abcdef\" abcdefg"))
+	s.Split(ScanTokens)
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	if want := []string{"ab", "abcdefg"}; !slices.Equal(got, want) {
+		t.Errorf("ScanTokens found %q, want %q", got, want)
+	}
+}
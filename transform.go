@@ -0,0 +1,249 @@
+package r85
+
+import (
+	"bytes"
+
+	"golang.org/x/text/transform"
+)
+
+// EncodeTransformer returns a transformer that encodes with r85: see
+// [Encoding.EncodeTransformer].
+func EncodeTransformer() transform.Transformer {
+	return StdEncoding.EncodeTransformer()
+}
+
+// DecodeTransformer returns a transformer that decodes r85: see
+// [Encoding.DecodeTransformer].
+func DecodeTransformer() transform.Transformer {
+	return StdEncoding.DecodeTransformer()
+}
+
+// EncodeTransformer returns a [transform.Transformer] whose output is
+// the text that enc.NewEncoder would write for its input, for use in
+// pipelines such as [transform.Chain].  A final 1–3 bytes are held back,
+// with [transform.ErrShortSrc], until atEOF.
+func (enc *Encoding) EncodeTransformer() transform.Transformer {
+	return &encodeTransformer{enc: enc}
+}
+
+type encodeTransformer struct {
+	enc     *Encoding
+	started bool // whether the prefix has been written
+	done    bool // whether the final block and suffix have been written
+	zeros   int  // all-zero blocks held back: see encodeStream
+	pending bytes.Buffer
+	lines   *lineWriter // inserts group separators into pending, or nil
+	buf     [3840]byte
+}
+
+func (t *encodeTransformer) Reset() {
+	t.started, t.done, t.zeros, t.lines = false, false, 0, nil
+	t.pending.Reset()
+}
+
+// text appends encoded text to the pending output, grouping it if
+// need be.
+func (t *encodeTransformer) text(b []byte) {
+	if t.lines != nil {
+		t.lines.Write(b)
+	} else {
+		t.pending.Write(b)
+	}
+}
+
+func (t *encodeTransformer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for {
+		n, _ := t.pending.Read(dst[nDst:])
+		nDst += n
+		if t.pending.Len() > 0 {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		if t.done {
+			return nDst, nSrc, nil
+		}
+		if !t.started {
+			t.started = true
+			if t.enc.groups > 0 {
+				// Start the first group after the prefix.
+				t.lines = &lineWriter{w: &t.pending, width: t.enc.groups, col: -len(t.enc.prefix), sep: []byte(t.enc.sep)}
+			}
+			t.text([]byte(t.enc.prefix))
+			continue
+		}
+		rest := src[nSrc:]
+		if len(rest) >= 4 {
+			n, k := t.enc.encodeStream(t.buf[:], rest, &t.zeros)
+			t.text(t.buf[:n])
+			nSrc += k
+			continue
+		}
+		if !atEOF {
+			if len(rest) > 0 {
+				return nDst, nSrc, transform.ErrShortSrc
+			}
+			return nDst, nSrc, nil
+		}
+		if t.zeros > 0 {
+			t.text(t.buf[:t.enc.endZeros(t.buf[:], &t.zeros)])
+			continue
+		}
+		if len(rest) > 0 {
+			if t.enc.partial == PartialNone {
+				return nDst, nSrc, ErrPartialBlock
+			}
+			t.text(t.buf[:t.enc.encodeBlocks(t.buf[:], rest)])
+			nSrc += len(rest)
+		}
+		// The suffix is not part of any group.
+		t.pending.WriteString(t.enc.suffix)
+		t.done = true
+	}
+}
+
+// DecodeTransformer returns a [transform.Transformer] that decodes its
+// input as enc.NewDecoder would, for use in pipelines such as
+// [transform.Chain].  Like the decoder, it keeps the digits of a
+// partial block until more input completes it, or until atEOF, when
+// they are decoded as the final block.  Errors are reported with offsets
+// counted from the start of the input.
+func (enc *Encoding) DecodeTransformer() transform.Transformer {
+	return &decodeTransformer{enc: enc}
+}
+
+type decodeTransformer struct {
+	enc   *Encoding
+	pos   int  // offset of the next input byte
+	body  bool // whether any prefix has been skipped
+	done  bool // whether the suffix has been read
+	final bool // whether a padded final block has been decoded
+	held  []byte // digits of a partial block, without skipped bytes
+	start int    // offset of the first held digit
+	join  []byte // the held digits followed by the text to decode
+}
+
+func (t *decodeTransformer) Reset() {
+	*t = decodeTransformer{enc: t.enc, held: t.held[:0], join: t.join[:0]}
+}
+
+func (t *decodeTransformer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	defer func() { t.pos += nSrc }()
+	enc := t.enc
+	if t.done {
+		// Like Decode, ignore anything after the end delimiter.
+		return 0, len(src), nil
+	}
+	if !t.body && enc.prefix != "" {
+		for nSrc < len(src) && isSpace(src[nSrc]) {
+			nSrc++
+		}
+		rest := src[nSrc:]
+		switch {
+		case bytes.HasPrefix(rest, []byte(enc.prefix)):
+			nSrc += len(enc.prefix)
+		case !atEOF && bytes.HasPrefix([]byte(enc.prefix), rest):
+			return 0, nSrc, transform.ErrShortSrc
+		}
+	}
+	t.body = true
+
+	// end is the end of the text to decode now, and last is whether the
+	// text ends there.
+	end, last, suffix := len(src), atEOF, false
+	if enc.suffix != "" {
+		if i := bytes.Index(src[nSrc:], []byte(enc.suffix)); i >= 0 {
+			end, last, suffix = nSrc+i, true, true
+		} else if atEOF {
+			return 0, nSrc, CorruptInputError{Reason: "missing end delimiter"}
+		} else {
+			// Hold back what may be the start of the suffix.
+			for k := min(len(enc.suffix)-1, end-nSrc); k > 0; k-- {
+				if bytes.HasSuffix(src[:end], []byte(enc.suffix[:k])) {
+					end -= k
+					break
+				}
+			}
+		}
+	}
+	text := src[nSrc:end]
+
+	if t.final {
+		// Only skipped characters may follow a padded final block.
+		for i, c := range text {
+			if enc.decodeMap[c] != digitSkip {
+				return 0, nSrc, CorruptInputError{Reason: "data after padded final block", Offset: t.pos + nSrc + i}
+			}
+		}
+		nSrc += len(text)
+		text = nil
+	}
+
+	// Put the held digits of a partial block before the text.  base is
+	// the offset in the input of the text after them.
+	base, h := t.pos+nSrc, len(t.held)
+	if h > 0 {
+		t.join = append(append(t.join[:0], t.held...), text...)
+		text = t.join
+	}
+
+	// Decode the complete blocks, or all of the text if it ends here,
+	// as far as they fit in dst.
+	whole := len(text)
+	if !last {
+		whole, _, _ = enc.scanBlocks(text)
+	}
+	n := whole
+	for n > 0 && enc.decodedLen(text[:n]) > len(dst) {
+		n, _, _ = enc.scanBlocks(text[:n/2])
+	}
+	nDst, _, err = enc.decodeBlocks(dst, text[:n])
+	if ce, ok := err.(CorruptInputError); ok {
+		if ce.Offset < h {
+			ce.Offset = t.start
+		} else {
+			ce.Offset += base - h
+		}
+		return nDst, nSrc, ce
+	} else if err != nil {
+		return nDst, nSrc, err
+	}
+	if enc.segment == 0 && nDst%4 != 0 {
+		t.final = true
+	}
+	if n > 0 {
+		// The first block took any held digits.
+		nSrc += n - h
+		t.held = t.held[:0]
+	}
+	switch {
+	case n < whole:
+		return nDst, nSrc, transform.ErrShortDst
+	case n < len(text):
+		// Keep the digits of a partial block, so that the skipped bytes
+		// among them need not be held back.
+		for i := max(n, h); i < len(text); i++ {
+			if enc.decodeMap[text[i]] == digitSkip {
+				continue
+			}
+			if len(t.held) == 0 {
+				t.start = base + i - h
+			}
+			t.held = append(t.held, text[i])
+		}
+		nSrc = end
+	}
+	switch {
+	case end < len(src) && !suffix:
+		// Hold back what may be part of the suffix.
+		return nDst, nSrc, transform.ErrShortSrc
+	case suffix:
+		t.done = true
+		return nDst, len(src), nil
+	}
+	return nDst, nSrc, nil
+}
+
+// isSpace reports whether c is ASCII white space, which may come before
+// the framing prefix.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == '\v'
+}
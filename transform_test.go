@@ -0,0 +1,147 @@
+package r85
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/transform"
+)
+
+var transformEncodings = []struct {
+	name string
+	enc  *Encoding
+}{
+	{"std", StdEncoding},
+	{"padded", PaddedEncoding},
+	{"r85z", R85ZEncoding},
+	{"adobe", AdobeEncoding},
+	{"grouped", StdEncoding.WithGroups(10, "\n")},
+	{"z85", Z85Encoding},
+}
+
+// transformChunks runs t over src, giving it at most srcChunk bytes of
+// input and dstSize bytes of output space at a time.
+func transformChunks(t transform.Transformer, src []byte, srcChunk, dstSize int) ([]byte, error) {
+	var out []byte
+	dst := make([]byte, dstSize)
+	avail := 0
+	for {
+		avail = min(len(src), max(avail, srcChunk))
+		atEOF := avail == len(src)
+		nDst, nSrc, err := t.Transform(dst, src[:avail], atEOF)
+		out = append(out, dst[:nDst]...)
+		src, avail = src[nSrc:], avail-nSrc
+		switch err {
+		case nil:
+			if atEOF {
+				return out, nil
+			}
+		case transform.ErrShortSrc:
+			if atEOF {
+				return out, err
+			}
+			avail += srcChunk
+		case transform.ErrShortDst:
+			if nDst == 0 && nSrc == 0 {
+				return out, err
+			}
+		default:
+			return out, err
+		}
+	}
+}
+
+// TestTransformers checks that the transformers match EncodeToString
+// and DecodeString when given their input and output space in pieces.
+func TestTransformers(t *testing.T) {
+	for _, te := range transformEncodings {
+		for _, n := range []int{0, 1, 5, 64, 1000} {
+			src := makeSrc(n)
+			if te.enc.partial == PartialNone {
+				src = src[:n&^3]
+			}
+			copy(src[len(src)/2:], make([]byte, min(len(src)/2, 40)))
+			text := te.enc.EncodeToString(src)
+			if got, _, err := transform.Bytes(te.enc.EncodeTransformer(), src); err != nil || string(got) != text {
+				t.Errorf("%s: encoding %d bytes: got %q, %v; want %q", te.name, n, got, err, text)
+			}
+			if got, _, err := transform.Bytes(te.enc.DecodeTransformer(), []byte(text)); err != nil || !bytes.Equal(got, src) {
+				t.Errorf("%s: decoding %d bytes: got %d bytes, %v", te.name, n, len(got), err)
+			}
+			for _, sz := range [][2]int{{1, 8}, {3, 5}, {7, 400}} {
+				got, err := transformChunks(te.enc.EncodeTransformer(), src, sz[0], sz[1])
+				if err != nil || string(got) != text {
+					t.Errorf("%s: encoding %d bytes in chunks %v: got %q, %v; want %q", te.name, n, sz, got, err, text)
+				}
+				if te.enc.zeroRun != 0 && sz[1] < 4*maxZeroRun {
+					// A zero run decodes to as many as 4*maxZeroRun bytes
+					// and is never split, so a smaller dst stops at the
+					// first run that does not fit, with ErrShortDst and
+					// the data before it.  Check that much, then decode
+					// again with room for any run.
+					got, err = transformChunks(te.enc.DecodeTransformer(), []byte(text), sz[0], sz[1])
+					if err != nil && err != transform.ErrShortDst || !bytes.HasPrefix(src, got) {
+						t.Errorf("%s: decoding %d bytes in chunks %v: got %d bytes, %v", te.name, n, sz, len(got), err)
+					}
+					sz[1] = 4 * maxZeroRun
+				}
+				got, err = transformChunks(te.enc.DecodeTransformer(), []byte(text), sz[0], sz[1])
+				if err != nil || !bytes.Equal(got, src) {
+					t.Errorf("%s: decoding %d bytes in chunks %v: got %d bytes, %v", te.name, n, sz, len(got), err)
+				}
+			}
+		}
+	}
+}
+
+// TestTransformChain checks a round trip through chained transformers.
+func TestTransformChain(t *testing.T) {
+	src := makeSrc(10000)
+	chain := transform.Chain(EncodeTransformer(), DecodeTransformer())
+	if got, _, err := transform.Bytes(chain, src); err != nil || !bytes.Equal(got, src) {
+		t.Errorf("round trip: got %d bytes, %v", len(got), err)
+	}
+}
+
+// TestDecodeTransformerErrors checks the errors of the decode transformer
+// and that it skips the text around Adobe framing.
+func TestDecodeTransformerErrors(t *testing.T) {
+	_, _, err := transform.String(DecodeTransformer(), "(((((|||||")
+	if ce, ok := err.(CorruptInputError); !ok || ce.Offset != 5 {
+		t.Errorf("overflow: err = %v, want CorruptInputError at offset 5", err)
+	}
+	_, _, err = transform.String(DecodeTransformer(), "(((((((")
+	if err != nil {
+		t.Errorf("partial block: err = %v", err)
+	}
+	_, _, err = transform.String(DecodeTransformer(), "((((((")
+	if _, ok := err.(CorruptInputError); !ok {
+		t.Errorf("single trailing character: err = %v, want CorruptInputError", err)
+	}
+	_, _, err = transform.String(AdobeEncoding.DecodeTransformer(), "<~zz")
+	if _, ok := err.(CorruptInputError); !ok {
+		t.Errorf("missing suffix: err = %v, want CorruptInputError", err)
+	}
+	got, _, err := transform.String(AdobeEncoding.DecodeTransformer(), " <~z~> trailing")
+	if err != nil || got != "\x00\x00\x00\x00" {
+		t.Errorf("framed: got %q, %v", got, err)
+	}
+}
+
+// TestDecodeTransformerSkipped checks a block whose digits are far apart,
+// so that a partial block must be kept across the buffer of
+// transform.NewReader, and that an error in it is reported at its start.
+func TestDecodeTransformerSkipped(t *testing.T) {
+	text := "(((" + strings.Repeat(" ", 5000) + "((\n"
+	got, err := io.ReadAll(transform.NewReader(strings.NewReader(text), DecodeTransformer()))
+	if err != nil || string(got) != "\x00\x00\x00\x00" {
+		t.Errorf("got %q, %v; want 4 zero bytes", got, err)
+	}
+	text = "          |||" + strings.Repeat(" ", 5000) + "||((((("
+	_, err = io.ReadAll(transform.NewReader(strings.NewReader(text), DecodeTransformer()))
+	if ce, ok := err.(CorruptInputError); !ok || ce.Offset != 10 {
+		t.Errorf("overflow: err = %v, want CorruptInputError at offset 10", err)
+	}
+}
@@ -0,0 +1,146 @@
+package r85
+
+// The fixed-width integer formats are the encodings of the big-endian
+// bytes of the value: a uint32 is one 5-digit block, a uint64 two and a
+// 128-bit value four.  So FormatUint64(v) equals EncodeToString of the
+// 8 bytes of v in big-endian order, and all values of one size have the
+// same length.
+
+// AppendUint32 appends the 5-character r85 form of v to dst.
+func AppendUint32(dst []byte, v uint32) []byte {
+	return StdEncoding.AppendUint32(dst, v)
+}
+
+// AppendUint64 appends the 10-character r85 form of v to dst.
+func AppendUint64(dst []byte, v uint64) []byte {
+	return StdEncoding.AppendUint64(dst, v)
+}
+
+// AppendUint128 appends the 20-character r85 form of the 128-bit value
+// hi<<64 | lo to dst.
+func AppendUint128(dst []byte, hi, lo uint64) []byte {
+	return StdEncoding.AppendUint128(dst, hi, lo)
+}
+
+// FormatUint32 returns the 5-character r85 form of v.
+func FormatUint32(v uint32) string {
+	return StdEncoding.FormatUint32(v)
+}
+
+// FormatUint64 returns the 10-character r85 form of v.
+func FormatUint64(v uint64) string {
+	return StdEncoding.FormatUint64(v)
+}
+
+// FormatUint128 returns the 20-character r85 form of hi<<64 | lo.
+func FormatUint128(hi, lo uint64) string {
+	return StdEncoding.FormatUint128(hi, lo)
+}
+
+// ParseUint32 parses the 5-character r85 form of a uint32.
+func ParseUint32(s string) (uint32, error) {
+	return StdEncoding.ParseUint32(s)
+}
+
+// ParseUint64 parses the 10-character r85 form of a uint64.
+func ParseUint64(s string) (uint64, error) {
+	return StdEncoding.ParseUint64(s)
+}
+
+// ParseUint128 parses the 20-character r85 form of a 128-bit value, and
+// returns its high and low 64 bits.
+func ParseUint128(s string) (hi, lo uint64, err error) {
+	return StdEncoding.ParseUint128(s)
+}
+
+// AppendUint32 appends the 5-digit form of v in enc's alphabet to dst.
+// Shortcuts and framing are not used.
+func (enc *Encoding) AppendUint32(dst []byte, v uint32) []byte {
+	dst = append(dst, 0, 0, 0, 0, 0)
+	enc.putDigits(dst[len(dst)-5:], v)
+	return dst
+}
+
+// AppendUint64 appends the 10-digit form of v in enc's alphabet to dst.
+func (enc *Encoding) AppendUint64(dst []byte, v uint64) []byte {
+	dst = enc.AppendUint32(dst, uint32(v>>32))
+	return enc.AppendUint32(dst, uint32(v))
+}
+
+// AppendUint128 appends the 20-digit form of hi<<64 | lo in enc's
+// alphabet to dst.
+func (enc *Encoding) AppendUint128(dst []byte, hi, lo uint64) []byte {
+	dst = enc.AppendUint64(dst, hi)
+	return enc.AppendUint64(dst, lo)
+}
+
+// FormatUint32 returns the 5-digit form of v in enc's alphabet.
+func (enc *Encoding) FormatUint32(v uint32) string {
+	var buf [5]byte
+	return string(enc.AppendUint32(buf[:0], v))
+}
+
+// FormatUint64 returns the 10-digit form of v in enc's alphabet.
+func (enc *Encoding) FormatUint64(v uint64) string {
+	var buf [10]byte
+	return string(enc.AppendUint64(buf[:0], v))
+}
+
+// FormatUint128 returns the 20-digit form of hi<<64 | lo in enc's
+// alphabet.
+func (enc *Encoding) FormatUint128(hi, lo uint64) string {
+	var buf [20]byte
+	return string(enc.AppendUint128(buf[:0], hi, lo))
+}
+
+// ParseUint32 parses the 5-digit form of a uint32 in enc's alphabet.
+// s must be exactly 5 digits; nothing is skipped.  Errors are reported as
+// a [CorruptInputError].
+func (enc *Encoding) ParseUint32(s string) (uint32, error) {
+	if len(s) != 5 {
+		return 0, CorruptInputError{Reason: "integer is not 5 characters long"}
+	}
+	return enc.parseWord(s)
+}
+
+// ParseUint64 parses the 10-digit form of a uint64 in enc's alphabet.
+func (enc *Encoding) ParseUint64(s string) (uint64, error) {
+	if len(s) != 10 {
+		return 0, CorruptInputError{Reason: "integer is not 10 characters long"}
+	}
+	hi, err := enc.parseWord(s[:5])
+	if err != nil {
+		return 0, err
+	}
+	lo, err := enc.parseWord(s[5:])
+	return uint64(hi)<<32 | uint64(lo), err
+}
+
+// ParseUint128 parses the 20-digit form of a 128-bit value in enc's
+// alphabet, and returns its high and low 64 bits.
+func (enc *Encoding) ParseUint128(s string) (hi, lo uint64, err error) {
+	if len(s) != 20 {
+		return 0, 0, CorruptInputError{Reason: "integer is not 20 characters long"}
+	}
+	if hi, err = enc.ParseUint64(s[:10]); err != nil {
+		return 0, 0, err
+	}
+	lo, err = enc.ParseUint64(s[10:])
+	return hi, lo, err
+}
+
+// parseWord parses one 5-digit block.
+func (enc *Encoding) parseWord(s string) (uint32, error) {
+	var acc uint64
+	for i := range 5 {
+		v := enc.decodeMap[s[i]]
+		if v >= 85 {
+			return 0, CorruptInputError{Reason: "invalid character in integer"}
+		}
+		acc = acc*85 + uint64(v)
+	}
+	if acc > 0xFFFFFFFF {
+		return 0, CorruptInputError{Reason: "value overflow in 5-character block"}
+	}
+	return uint32(acc), nil
+}
@@ -0,0 +1,73 @@
+package r85
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// TestUintMatchesEncode verifies that the fixed-width forms equal the
+// encodings of the big-endian bytes, and round-trip.
+func TestUintMatchesEncode(t *testing.T) {
+	for _, v := range []uint64{0, 1, 84, 85, math.MaxUint32, math.MaxUint32 + 1, 0x0123456789abcdef, math.MaxUint64} {
+		var b [16]byte
+		binary.BigEndian.PutUint64(b[:8], v)
+		binary.BigEndian.PutUint64(b[8:], ^v)
+
+		if got, want := FormatUint32(uint32(v)), EncodeToString(b[4:8]); got != want {
+			t.Errorf("FormatUint32(%#x) = %q, want %q", uint32(v), got, want)
+		}
+		if got, err := ParseUint32(FormatUint32(uint32(v))); err != nil || got != uint32(v) {
+			t.Errorf("ParseUint32 of %#x = %#x, %v", uint32(v), got, err)
+		}
+		if got, want := FormatUint64(v), EncodeToString(b[:8]); got != want {
+			t.Errorf("FormatUint64(%#x) = %q, want %q", v, got, want)
+		}
+		if got, err := ParseUint64(FormatUint64(v)); err != nil || got != v {
+			t.Errorf("ParseUint64 of %#x = %#x, %v", v, got, err)
+		}
+		if got, want := FormatUint128(v, ^v), EncodeToString(b[:]); got != want {
+			t.Errorf("FormatUint128(%#x, %#x) = %q, want %q", v, ^v, got, want)
+		}
+		if hi, lo, err := ParseUint128(FormatUint128(v, ^v)); err != nil || hi != v || lo != ^v {
+			t.Errorf("ParseUint128 of %#x = %#x, %#x, %v", v, hi, lo, err)
+		}
+	}
+}
+
+// TestParseUintErrors tests rejection of malformed integers.
+func TestParseUintErrors(t *testing.T) {
+	for _, s := range []string{"", "((((", "((((((", "(((( ", "|||||"} {
+		if _, err := ParseUint32(s); err == nil {
+			t.Errorf("ParseUint32(%q): expected error", s)
+		}
+	}
+	if v, err := ParseUint64("(((((" + FormatUint32(math.MaxUint32)); err != nil || v != math.MaxUint32 {
+		t.Errorf("ParseUint64 = %#x, %v", v, err)
+	}
+	if _, err := ParseUint64("|||||((((("); err == nil {
+		t.Error("ParseUint64 accepted an overflowing block")
+	}
+	if _, _, err := ParseUint128("(((((((((((((((((((!"); err == nil {
+		t.Error("ParseUint128 accepted a reserved character")
+	}
+}
+
+// TestAppendUintAllocs verifies that appending to a large enough slice,
+// and parsing a valid string, do not allocate.
+func TestAppendUintAllocs(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	const s = "(((((((((/" // the 10-character form of 7
+	if v, err := ParseUint64(s); v != 7 || err != nil {
+		t.Fatalf("ParseUint64(%q) = %d, %v; want 7", s, v, err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		b := AppendUint32(buf[:0], 7)
+		b = AppendUint64(b, 7)
+		AppendUint128(b, 7, 7)
+		ParseUint64(s)
+	})
+	if allocs != 0 {
+		t.Errorf("allocs = %v, want 0", allocs)
+	}
+}
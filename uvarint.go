@@ -0,0 +1,95 @@
+package r85
+
+// A uvarint is a self-delimiting unsigned integer of 1 to 11 digits.
+// A first digit d below 64 is the value itself.  A first digit 64+k-1,
+// for k from 1 to 10, is followed by k digits holding the value minus
+// uvarintBase[k], as a k-digit base-85 number.  First digits 74 to 84
+// are not used.
+
+// uvarintDirect is the number of values held by the first digit alone.
+const uvarintDirect = 64
+
+// MaxUvarintLen is the maximum length of an r85 uvarint.
+const MaxUvarintLen = 11
+
+// uvarintBase[k] is the smallest value that is written with k digits
+// after the first, and uvarintPow[k] is 85^k.  85^10 > 2^64, so
+// uvarintPow stops at 85^9.
+var (
+	uvarintBase [MaxUvarintLen]uint64
+	uvarintPow  [MaxUvarintLen - 1]uint64
+)
+
+func init() {
+	uvarintBase[1], uvarintPow[0] = uvarintDirect, 1
+	for k := 1; k < MaxUvarintLen-1; k++ {
+		uvarintPow[k] = uvarintPow[k-1] * 85
+		uvarintBase[k+1] = uvarintBase[k] + uvarintPow[k]
+	}
+}
+
+// AppendUvarint appends the r85 uvarint form of v to dst.
+func AppendUvarint(dst []byte, v uint64) []byte {
+	return StdEncoding.AppendUvarint(dst, v)
+}
+
+// Uvarint decodes an r85 uvarint from the start of src, as written by
+// [AppendUvarint], and returns the value and the number of bytes read.
+// If an error occurred, the value is 0 and n is 0 if src is too short,
+// or -1 if src does not start with a valid uvarint.
+func Uvarint(src []byte) (v uint64, n int) {
+	return StdEncoding.Uvarint(src)
+}
+
+// AppendUvarint appends the uvarint form of v, in enc's alphabet, to dst.
+// Values below 64 take one character, and the length grows with the
+// value up to 11 characters.  Uvarints are self-delimiting, so several
+// can be appended one after another and read back in order.
+func (enc *Encoding) AppendUvarint(dst []byte, v uint64) []byte {
+	if v < uvarintDirect {
+		return append(dst, enc.encode[v])
+	}
+	k := 1
+	for k < MaxUvarintLen-1 && v-uvarintBase[k] >= uvarintPow[k] {
+		k++
+	}
+	v -= uvarintBase[k]
+	dst = append(dst, enc.encode[uvarintDirect+k-1])
+	for i := k - 1; i >= 0; i-- {
+		dst = append(dst, enc.encode[v/uvarintPow[i]%85])
+	}
+	return dst
+}
+
+// Uvarint decodes a uvarint in enc's alphabet from the start of src, and
+// returns the value and the number of bytes read, like [Uvarint].
+func (enc *Encoding) Uvarint(src []byte) (v uint64, n int) {
+	if len(src) == 0 {
+		return 0, 0
+	}
+	d := enc.decodeMap[src[0]]
+	switch {
+	case d < uvarintDirect:
+		return uint64(d), 1
+	case d >= uvarintDirect+MaxUvarintLen-1:
+		return 0, -1
+	}
+	k := int(d-uvarintDirect) + 1
+	if len(src) < 1+k {
+		return 0, 0
+	}
+	for _, c := range src[1 : 1+k] {
+		d := enc.decodeMap[c]
+		if d >= 85 {
+			return 0, -1
+		}
+		if v > (^uint64(0)-uint64(d))/85 {
+			return 0, -1
+		}
+		v = v*85 + uint64(d)
+	}
+	if v > ^uint64(0)-uvarintBase[k] {
+		return 0, -1
+	}
+	return v + uvarintBase[k], 1 + k
+}
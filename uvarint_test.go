@@ -0,0 +1,73 @@
+package r85
+
+import (
+	"math"
+	"testing"
+)
+
+// TestUvarintRoundtrip verifies that concatenated uvarints round-trip.
+func TestUvarintRoundtrip(t *testing.T) {
+	values := []uint64{0, 1, 63, 64, 64 + 84, 64 + 85, 64 + 85 + 85*85 - 1, 64 + 85 + 85*85, math.MaxUint32, math.MaxUint64 - 1, math.MaxUint64}
+	for k := range uvarintBase {
+		values = append(values, uvarintBase[k], uvarintBase[k]-1)
+	}
+	var buf []byte
+	for _, v := range values {
+		buf = AppendUvarint(buf, v)
+	}
+	for _, v := range values {
+		got, n := Uvarint(buf)
+		if n <= 0 || got != v {
+			t.Fatalf("Uvarint = %d, %d, want %d", got, n, v)
+		}
+		if n > MaxUvarintLen {
+			t.Fatalf("uvarint of %d is %d characters long", v, n)
+		}
+		buf = buf[n:]
+	}
+	if len(buf) != 0 {
+		t.Errorf("%d bytes left over", len(buf))
+	}
+}
+
+// TestUvarintKnownValues tests uvarint lengths and encodings.
+func TestUvarintKnownValues(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want string
+	}{
+		{0, "("},
+		{63, "g"},
+		{64, "h("},
+		{148, "h|"},
+		{149, "i(("},
+		{math.MaxUint64, "qv]a)qtly>="},
+	}
+	for _, tt := range tests {
+		if got := string(AppendUvarint(nil, tt.v)); got != tt.want {
+			t.Errorf("AppendUvarint(%d) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+// TestUvarintErrors tests truncated, invalid and overflowing uvarints.
+func TestUvarintErrors(t *testing.T) {
+	tests := []struct {
+		in string
+		n  int
+	}{
+		{"", 0},
+		{"h", 0},
+		{"q((((", 0},
+		{"r", -1},
+		{"|", -1},
+		{" ", -1},
+		{"h ", -1},
+		{"q||||||||||", -1},
+	}
+	for _, tt := range tests {
+		if v, n := Uvarint([]byte(tt.in)); v != 0 || n != tt.n {
+			t.Errorf("Uvarint(%q) = %d, %d, want 0, %d", tt.in, v, n, tt.n)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package r85
+
+// z85Alphabet is the alphabet of ZeroMQ RFC 32/Z85.
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+// Z85Encoding is ZeroMQ's Z85 encoding, as specified by RFC 32.  It uses
+// [PartialNone]: binary input must be a multiple of 4 bytes and text
+// input a multiple of 5 digits.  Encode, EncodeToString and
+// EncodeChecked panic if the input is not a multiple of 4 bytes, so pass
+// the length of input that is not known to be whole blocks to
+// CheckEncodeLen first; the Close method of an encoder from NewEncoder
+// returns [ErrPartialBlock] instead.
+var Z85Encoding = NewEncoding(z85Alphabet).WithPartial(PartialNone)
+
+// Z85PaddedEncoding is Z85 extended to arbitrary lengths with
+// [PartialTruncate], so a final block of n bytes becomes n+1 characters.
+// Its output for inputs that are a multiple of 4 bytes is plain Z85.
+var Z85PaddedEncoding = NewEncoding(z85Alphabet).WithPartial(PartialTruncate)
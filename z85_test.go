@@ -0,0 +1,140 @@
+package r85
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// z85Reference is a straightforward Z85 encoder used to check the
+// remapped SIMD path.
+func z85Reference(src []byte) string {
+	var b strings.Builder
+	for i := 0; i+4 <= len(src); i += 4 {
+		v := uint32(src[i])<<24 | uint32(src[i+1])<<16 | uint32(src[i+2])<<8 | uint32(src[i+3])
+		var block [5]byte
+		for j := 4; j >= 0; j-- {
+			block[j] = z85Alphabet[v%85]
+			v /= 85
+		}
+		b.Write(block[:])
+	}
+	return b.String()
+}
+
+// TestZ85KnownValue tests the example from RFC 32.
+func TestZ85KnownValue(t *testing.T) {
+	src := []byte{0x86, 0x4F, 0xD2, 0x6F, 0xB5, 0x59, 0xF7, 0x5B}
+	if got := Z85Encoding.EncodeToString(src); got != "HelloWorld" {
+		t.Errorf("Encode = %q, want %q", got, "HelloWorld")
+	}
+	got, err := Z85Encoding.DecodeString("HelloWorld")
+	if err != nil || !bytes.Equal(got, src) {
+		t.Errorf("Decode = %v, %v, want %v", got, err, src)
+	}
+}
+
+// TestZ85MatchesReference compares Z85 output, including the remapped
+// SIMD path, against a scalar reference.
+func TestZ85MatchesReference(t *testing.T) {
+	for _, n := range []int{0, 4, 60, 64, 68, 128, 1000, 4096} {
+		src := makeSrc(n)
+		want := z85Reference(src)
+		got := Z85Encoding.EncodeToString(src)
+		if got != want {
+			t.Fatalf("Encode(%d bytes) mismatch", n)
+		}
+		dec, err := Z85Encoding.DecodeString(got)
+		if err != nil || !bytes.Equal(dec, src) {
+			t.Fatalf("Decode(%d bytes): err = %v, match = %v", n, err, bytes.Equal(dec, src))
+		}
+	}
+}
+
+// TestZ85Strict verifies the RFC 32 length rules.
+func TestZ85Strict(t *testing.T) {
+	if _, err := Z85Encoding.DecodeString("HelloWorl"); err == nil {
+		t.Error("Decode of 9 characters: expected error, got nil")
+	}
+	if err := Z85Encoding.CheckEncodeLen(3); err != ErrPartialBlock {
+		t.Errorf("CheckEncodeLen(3) = %v, want ErrPartialBlock", err)
+	}
+	if err := Z85Encoding.CheckEncodeLen(8); err != nil {
+		t.Errorf("CheckEncodeLen(8) = %v, want nil", err)
+	}
+	if err := StdEncoding.CheckEncodeLen(3); err != nil {
+		t.Errorf("StdEncoding.CheckEncodeLen(3) = %v, want nil", err)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != ErrPartialBlock {
+				t.Errorf("Encode of 3 bytes: panic = %v, want ErrPartialBlock", r)
+			}
+		}()
+		Z85Encoding.EncodeToString([]byte{1, 2, 3})
+	}()
+	w := Z85Encoding.NewEncoder(io.Discard)
+	w.Write([]byte{1, 2, 3, 4, 5})
+	if err := w.Close(); err != ErrPartialBlock {
+		t.Errorf("Close after 5 bytes: err = %v, want ErrPartialBlock", err)
+	}
+}
+
+// TestZ85SkipInBlock tests that a skipped character in a run long
+// enough for the SIMD path is skipped.
+func TestZ85SkipInBlock(t *testing.T) {
+	src := makeSrc(256)
+	text := Z85Encoding.EncodeToString(src)
+	text = text[:172] + "\n" + text[172:]
+	dec, err := Z85Encoding.DecodeString(text)
+	if err != nil || !bytes.Equal(dec, src) {
+		t.Errorf("Decode: err = %v, match = %v", err, bytes.Equal(dec, src))
+	}
+}
+
+// TestHighAlphabet tests an alphabet of bytes from 0x80 up, which the
+// SIMD kernels look up for encoding but leave to the scalar remap when
+// decoding.
+func TestHighAlphabet(t *testing.T) {
+	var alphabet [85]byte
+	for i := range alphabet {
+		alphabet[i] = byte(0xFF - i)
+	}
+	enc := NewEncoding(string(alphabet[:]))
+	src := makeSrc(1000)
+	got := enc.EncodeToString(src)
+	want := []byte(EncodeToString(src))
+	for i, c := range want {
+		want[i] = alphabet[StdEncoding.decodeMap[c]]
+	}
+	if got != string(want) {
+		t.Fatal("Encode differs from r85 with the alphabet replaced")
+	}
+	dec, err := enc.DecodeString(got)
+	if err != nil || !bytes.Equal(dec, src) {
+		t.Errorf("Decode: err = %v, match = %v", err, bytes.Equal(dec, src))
+	}
+}
+
+// TestZ85Padded tests the arbitrary-length Z85 variant.
+func TestZ85Padded(t *testing.T) {
+	for n := range 20 {
+		src := makeSrc(n)
+		enc := Z85PaddedEncoding.EncodeToString(src)
+		if len(enc) != MaxEncodedLen(n) {
+			t.Errorf("Encode(%d bytes): length = %d, want %d", n, len(enc), MaxEncodedLen(n))
+		}
+		if n%4 == 0 && enc != z85Reference(src) {
+			t.Errorf("Encode(%d bytes) differs from Z85", n)
+		}
+		var buf bytes.Buffer
+		w := Z85PaddedEncoding.NewEncoder(&buf)
+		w.Write(src)
+		w.Close()
+		got, err := io.ReadAll(Z85PaddedEncoding.NewDecoder(&buf))
+		if err != nil || !bytes.Equal(got, src) {
+			t.Errorf("streaming roundtrip(%d bytes): err = %v, got %v", n, err, got)
+		}
+	}
+}